@@ -0,0 +1,212 @@
+package vayu
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat selects the output format of the AccessLog middleware.
+type AccessLogFormat int
+
+const (
+	// AccessLogCommon writes Apache Common Log Format lines.
+	AccessLogCommon AccessLogFormat = iota
+	// AccessLogCombined writes Apache Combined Log Format lines
+	// (Common Log Format plus referer and user agent).
+	AccessLogCombined
+	// AccessLogJSON writes one JSON object per line.
+	AccessLogJSON
+	// AccessLogSlog logs each request through the request-scoped slog logger.
+	AccessLogSlog
+)
+
+// clfTimeFormat is the timestamp layout used by the Common Log Format.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// AccessLogConfig configures the AccessLog middleware.
+type AccessLogConfig struct {
+	// Format selects the log line format. Defaults to AccessLogCommon.
+	Format AccessLogFormat
+
+	// Output receives the formatted lines. Defaults to os.Stdout.
+	// It is not used by AccessLogSlog.
+	Output io.Writer
+
+	// Level is the slog level used by AccessLogSlog. Defaults to info.
+	Level slog.Level
+
+	// SkipPaths lists request paths that are never logged,
+	// e.g. "/healthz" or "/metrics".
+	SkipPaths []string
+
+	// Skip reports whether a request should not be logged.
+	// It is called after the handler has run.
+	Skip func(c *Context) bool
+}
+
+// AccessLogEntry holds the data recorded for a single request.
+type AccessLogEntry struct {
+	Time      time.Time     `json:"time"`
+	RemoteIP  string        `json:"remote_ip"`
+	Method    string        `json:"method"`
+	Path      string        `json:"path"`
+	Route     string        `json:"route,omitempty"`
+	Proto     string        `json:"proto"`
+	Status    int           `json:"status"`
+	Size      int           `json:"size"`
+	Latency   time.Duration `json:"latency_ns"`
+	UserAgent string        `json:"user_agent,omitempty"`
+	Referer   string        `json:"referer,omitempty"`
+	User      string        `json:"user,omitempty"`
+	RequestID string        `json:"request_id,omitempty"`
+}
+
+// AccessLog returns middleware that records one access log entry per request,
+// including status code, response size, remote IP, user agent, route pattern
+// and latency.
+func AccessLog(config AccessLogConfig) HandlerFunc {
+	out := config.Output
+	if out == nil {
+		out = os.Stdout
+	}
+	skipPaths := make(map[string]struct{}, len(config.SkipPaths))
+	for _, p := range config.SkipPaths {
+		skipPaths[p] = struct{}{}
+	}
+
+	// Serialize writes so concurrent requests never interleave lines
+	var mu sync.Mutex
+
+	return func(c *Context, next NextFunc) {
+		if _, ok := skipPaths[c.Request.URL.Path]; ok {
+			next()
+			return
+		}
+
+		start := time.Now()
+		next()
+
+		if config.Skip != nil && config.Skip(c) {
+			return
+		}
+
+		entry := newAccessLogEntry(c, start)
+
+		if config.Format == AccessLogSlog {
+			c.Logger().LogAttrs(c.Request.Context(), config.Level, "access",
+				slog.String("remote_ip", entry.RemoteIP),
+				slog.String("path", entry.Path),
+				slog.String("proto", entry.Proto),
+				slog.Int("status", entry.Status),
+				slog.Int("size", entry.Size),
+				slog.Duration("latency", entry.Latency),
+				slog.String("user_agent", entry.UserAgent),
+				slog.String("referer", entry.Referer),
+			)
+			return
+		}
+
+		line := formatAccessLog(config.Format, entry)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if _, err := out.Write(line); err != nil {
+			c.Logger().Error("error writing access log", "error", err)
+		}
+	}
+}
+
+// newAccessLogEntry collects the access log data for a completed request.
+func newAccessLogEntry(c *Context, start time.Time) AccessLogEntry {
+	r := c.Request
+	status := c.Writer.Status()
+	if status == 0 {
+		status = StatusOK
+	}
+
+	user, _, _ := r.BasicAuth()
+
+	return AccessLogEntry{
+		Time:      start,
+		RemoteIP:  c.ClientIP(),
+		Method:    r.Method,
+		Path:      r.URL.RequestURI(),
+		Route:     c.RoutePattern(),
+		Proto:     r.Proto,
+		Status:    status,
+		Size:      c.Writer.Size(),
+		Latency:   time.Since(start),
+		UserAgent: r.UserAgent(),
+		Referer:   r.Referer(),
+		User:      user,
+		RequestID: c.RequestID(),
+	}
+}
+
+// formatAccessLog renders an entry as a single newline-terminated line.
+func formatAccessLog(format AccessLogFormat, e AccessLogEntry) []byte {
+	if format == AccessLogJSON {
+		line, err := json.Marshal(e)
+		if err != nil {
+			// AccessLogEntry only holds strings, ints and times
+			line = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+		}
+		return append(line, '\n')
+	}
+
+	size := "-"
+	if e.Size > 0 {
+		size = strconv.Itoa(e.Size)
+	}
+	line := fmt.Sprintf(`%s - %s [%s] "%s" %d %s`,
+		e.RemoteIP,
+		clfField(e.User),
+		e.Time.Format(clfTimeFormat),
+		clfEscape(e.Method+" "+e.Path+" "+e.Proto),
+		e.Status,
+		size,
+	)
+	if format == AccessLogCombined {
+		line += fmt.Sprintf(` "%s" "%s"`, clfField(e.Referer), clfField(e.UserAgent))
+	}
+	return []byte(line + "\n")
+}
+
+// clfField returns "-" for empty values, as the Common Log Format requires,
+// and escapes other values with clfEscape.
+func clfField(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return clfEscape(s)
+}
+
+// clfEscape escapes client-supplied text as Apache does, so it can neither
+// break out of a quoted field nor start a forged log line: quotes and
+// backslashes get a backslash, and control and non-ASCII bytes become \xhh.
+func clfEscape(s string) string {
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '"' || ch == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(ch)
+		case ch < 0x20 || ch >= 0x7f:
+			b.WriteString(`\x`)
+			b.WriteByte(hex[ch>>4])
+			b.WriteByte(hex[ch&0x0f])
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,433 @@
+package vayu
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// ErrFieldMissing is wrapped by FieldError when a required value is absent.
+var ErrFieldMissing = errors.New("value is missing")
+
+// FieldError describes a single value that could not be bound.
+type FieldError struct {
+	// Field is the name of the struct field, or the JSON path for body errors.
+	Field string
+	// Source is the tag the value was read from: path, query, header,
+	// cookie, form or json.
+	Source string
+	// Key is the parameter, header, cookie or form key. It is empty for
+	// errors that concern the request body as a whole.
+	Key string
+	// Offset is the byte offset in a JSON body where the error was found,
+	// or zero when unknown.
+	Offset int64
+	Err    error
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	var msg string
+	switch {
+	case e.Key == "":
+		msg = fmt.Sprintf("%s body: %v", e.Source, e.Err)
+	case errors.Is(e.Err, ErrFieldMissing):
+		msg = fmt.Sprintf("required %s %s missing", sourceName(e.Source), e.Key)
+	default:
+		msg = fmt.Sprintf("%s %s: %v", sourceName(e.Source), e.Key, e.Err)
+	}
+	if e.Offset > 0 {
+		msg += fmt.Sprintf(" (at byte %d)", e.Offset)
+	}
+	return msg
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// BindingError collects every field that failed to bind.
+// Through the default error handler it produces a 400 Bad Request.
+type BindingError struct {
+	Errors []*FieldError
+}
+
+// Error implements the error interface.
+func (e *BindingError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the individual field errors.
+func (e *BindingError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, fe := range e.Errors {
+		errs[i] = fe
+	}
+	return errs
+}
+
+// StatusCode implements StatusCoder.
+func (e *BindingError) StatusCode() int {
+	return StatusBadRequest
+}
+
+// ErrorDetails implements ErrorDetailer with one entry per field error.
+func (e *BindingError) ErrorDetails() any {
+	details := make([]map[string]any, len(e.Errors))
+	for i, fe := range e.Errors {
+		detail := map[string]any{"source": fe.Source, "message": fe.Error()}
+		if fe.Key != "" {
+			detail["field"] = fe.Key
+		}
+		if fe.Offset > 0 {
+			detail["offset"] = fe.Offset
+		}
+		details[i] = detail
+	}
+	return details
+}
+
+// Bind populates a T from every part of the request in one call.
+// The body is decoded first with BindBody, so JSON bodies fill fields by
+// their `json` tags and form bodies fill fields tagged `form`. Fields tagged `path`, `query`,
+// `header` or `cookie` are then set from the request, and a field with
+// several tags takes the first value present in the order
+// path, query, header, cookie, form. All failures are returned together
+// as a *BindingError; a request that binds cleanly is then checked with
+// Validate.
+// Usage: req, err := vayu.Bind[CreateOrder](c)
+func Bind[T any](c *Context) (T, error) {
+	var result T
+	val := reflect.ValueOf(&result).Elem()
+	if val.Kind() != reflect.Struct {
+		return result, fmt.Errorf("vayu.Bind requires a struct type, got %s", val.Type())
+	}
+
+	var errs []*FieldError
+	sources := []tagSource{pathSource(c), querySource(c), headerSource(c), cookieSource(c)}
+
+	switch requestMediaType(c.Request) {
+	case "application/x-www-form-urlencoded", "multipart/form-data":
+		src, err := formSource(c)
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			return result, err
+		} else if err != nil {
+			errs = append(errs, &FieldError{Source: "form", Err: err})
+		} else {
+			sources = append(sources, src)
+			errs = append(errs, bindFormFiles(c.Request, val)...)
+		}
+	default:
+		if hasBody(c.Request) {
+			err := c.decodeBody(&result)
+			var be *BindingError
+			if errors.As(err, &be) {
+				errs = append(errs, be.Errors...)
+			} else if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	_, fieldErrs := bindFields(val, sources)
+	errs = append(errs, fieldErrs...)
+	if len(errs) > 0 {
+		return result, &BindingError{Errors: errs}
+	}
+	return result, Validate(&result)
+}
+
+// MustBind populates a T from the request and panics if binding fails.
+// Combined with ErrorHandlerMiddleware this results in a 400 Bad Request.
+// Usage: req := vayu.MustBind[CreateOrder](c)
+func MustBind[T any](c *Context) T {
+	result, err := Bind[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// requestMediaType returns the media type of the request's Content-Type
+// without parameters, or an empty string when there is none.
+func requestMediaType(r *http.Request) string {
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return contentType
+	}
+	return mediaType
+}
+
+// tagSource describes where bindFields reads field values from.
+type tagSource struct {
+	tag      string // struct tag naming the key, e.g. "query"
+	required bool   // whether every tagged field must be present
+	prefix   string // prepended to keys of nested struct fields, e.g. "filter."
+	lookup   func(key string) []string
+}
+
+// sourceName returns the name of a tag source used in error messages.
+func sourceName(tag string) string {
+	switch tag {
+	case "path":
+		return "path parameter"
+	case "query":
+		return "query parameter"
+	case "form":
+		return "form field"
+	case "json":
+		return "JSON field"
+	default:
+		return tag
+	}
+}
+
+// pathSource reads route parameters. Every tagged parameter is required.
+func pathSource(c *Context) tagSource {
+	return tagSource{
+		tag:      "path",
+		required: true,
+		lookup: func(key string) []string {
+			if value, ok := c.Params[key]; ok {
+				return []string{value}
+			}
+			return nil
+		},
+	}
+}
+
+// querySource reads URL query parameters, including repeated keys.
+func querySource(c *Context) tagSource {
+	query := c.Request.URL.Query()
+	return tagSource{
+		tag: "query",
+		lookup: func(key string) []string {
+			return query[key]
+		},
+	}
+}
+
+// headerSource reads request headers, including repeated headers.
+func headerSource(c *Context) tagSource {
+	return tagSource{
+		tag: "header",
+		lookup: func(key string) []string {
+			return c.Request.Header.Values(key)
+		},
+	}
+}
+
+// cookieSource reads request cookies.
+func cookieSource(c *Context) tagSource {
+	return tagSource{
+		tag: "cookie",
+		lookup: func(key string) []string {
+			cookie, err := c.Request.Cookie(key)
+			if err != nil {
+				return nil
+			}
+			return []string{cookie.Value}
+		},
+	}
+}
+
+// parseForm parses the request body as a URL-encoded or multipart form,
+// capped at the configured MaxBodyBytes. Oversized bodies produce an
+// *HTTPError with status 413.
+func (c *Context) parseForm() error {
+	r := c.Request
+	if r.PostForm != nil {
+		// Already parsed
+		return nil
+	}
+	var limit int64
+	if r.Body != nil {
+		body, bodyLimit, err := c.limitedBody()
+		if err != nil {
+			return err
+		}
+		r.Body, limit = body, bodyLimit
+	}
+
+	var err error
+	if requestMediaType(r) == "multipart/form-data" {
+		err = r.ParseMultipartForm(10 << 20) // 10MB in memory, the rest on disk
+	} else {
+		err = r.ParseForm()
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return bodyTooLarge(limit, err)
+	}
+	return err
+}
+
+// formSource parses the request body as a form and reads its fields,
+// including repeated fields.
+func formSource(c *Context) (tagSource, error) {
+	if err := c.parseForm(); err != nil {
+		return tagSource{}, err
+	}
+
+	form := c.Request.PostForm
+	return tagSource{
+		tag: "form",
+		lookup: func(key string) []string {
+			return form[key]
+		},
+	}, nil
+}
+
+// nonEmpty returns values without empty strings, or nil if none remain.
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// isNestedStruct reports whether fields of type t are bound field by field
+// rather than parsed from a single value.
+func isNestedStruct(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !isTextUnmarshaler(t) && !isFileField(reflect.PointerTo(t))
+}
+
+// bindFields sets each field of the struct val from the first source that
+// has a value for its tag. Fields may carry a `default` tag used when no
+// source has a value, and a `format` tag with the time.Time layout.
+// Embedded structs share the parent's keys; other struct fields bind their
+// own fields under the prefix "<tag>.". It reports whether any field
+// carried one of the sources' tags and returns one FieldError per failed field.
+func bindFields(val reflect.Value, sources []tagSource) (bool, []*FieldError) {
+	processed, _, errs := bindStruct(val, sources)
+	return processed, errs
+}
+
+// bindStruct implements bindFields and also reports whether any value was set.
+func bindStruct(val reflect.Value, sources []tagSource) (processed, set bool, errs []*FieldError) {
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if isFileField(field.Type) {
+			// Uploaded files are bound by bindFormFiles
+			continue
+		}
+
+		if isNestedStruct(field.Type) {
+			p, s, e := bindNested(val.Field(i), field, sources)
+			processed = processed || p
+			set = set || s
+			errs = append(errs, e...)
+			continue
+		}
+
+		required := field.Tag.Get("required") == "true"
+		format := field.Tag.Get("format")
+
+		var missing *FieldError
+		found := false
+		for _, src := range sources {
+			key := field.Tag.Get(src.tag)
+			if key == "" || key == "-" {
+				continue
+			}
+			processed = true
+			key = src.prefix + key
+
+			values := nonEmpty(src.lookup(key))
+			if len(values) == 0 {
+				required = required || src.required
+				if missing == nil {
+					missing = &FieldError{Field: field.Name, Source: src.tag, Key: key, Err: ErrFieldMissing}
+				}
+				continue
+			}
+			found = true
+			set = true
+
+			fieldValue := val.Field(i)
+			if !fieldValue.CanSet() {
+				errs = append(errs, &FieldError{Field: field.Name, Source: src.tag, Key: key,
+					Err: fmt.Errorf("field %s cannot be set (is it unexported?)", field.Name)})
+				break
+			}
+
+			// Convert the values to the appropriate field type
+			if err := setFieldFromValues(fieldValue, values, format); err != nil {
+				errs = append(errs, &FieldError{Field: field.Name, Source: src.tag, Key: key, Err: err})
+			}
+			break
+		}
+
+		if found || missing == nil {
+			continue
+		}
+		if def, ok := field.Tag.Lookup("default"); ok && val.Field(i).CanSet() {
+			if err := setFieldFromValues(val.Field(i), []string{def}, format); err != nil {
+				errs = append(errs, &FieldError{Field: field.Name, Source: missing.Source, Key: missing.Key,
+					Err: fmt.Errorf("invalid default: %w", err)})
+			}
+			continue
+		}
+		if required {
+			errs = append(errs, missing)
+		}
+	}
+
+	return processed, set, errs
+}
+
+// bindNested binds the fields of a nested or embedded struct field.
+// A pointer to a struct is only allocated when one of its fields is set.
+func bindNested(fieldValue reflect.Value, field reflect.StructField, sources []tagSource) (bool, bool, []*FieldError) {
+	nested := make([]tagSource, 0, len(sources))
+	for _, src := range sources {
+		if key := field.Tag.Get(src.tag); key != "" && key != "-" {
+			src.prefix += key + "."
+		} else if !field.Anonymous {
+			continue
+		}
+		nested = append(nested, src)
+	}
+	if len(nested) == 0 {
+		return false, false, nil
+	}
+
+	if fieldValue.Kind() != reflect.Pointer {
+		// Exported fields of an unexported embedded struct are still settable
+		return bindStruct(fieldValue, nested)
+	}
+	if !fieldValue.CanSet() {
+		return false, false, nil
+	}
+
+	target := reflect.New(field.Type.Elem())
+	if !fieldValue.IsNil() {
+		target.Elem().Set(fieldValue.Elem())
+	}
+	processed, set, errs := bindStruct(target.Elem(), nested)
+	if set {
+		fieldValue.Set(target)
+	}
+	return processed, set, errs
+}
@@ -0,0 +1,229 @@
+package vayu
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupportedMediaType is wrapped by the error BindBody returns when no
+// decoder is registered for the request's Content-Type.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// BodyDecoder decodes the request body into dest, which is a non-nil pointer.
+// Errors implementing StatusCoder control the response status; decoders
+// should return a *BindingError for malformed input.
+type BodyDecoder func(c *Context, dest any) error
+
+var (
+	bodyDecodersMu sync.RWMutex
+	bodyDecoders   = map[string]BodyDecoder{
+		"application/json":                  decodeJSON,
+		"application/xml":                   decodeXML,
+		"text/xml":                          decodeXML,
+		"application/x-www-form-urlencoded": decodeForm,
+		"multipart/form-data":               decodeForm,
+	}
+)
+
+// RegisterBodyDecoder registers the decoder BindBody uses for a media type,
+// such as "application/msgpack", replacing any existing one.
+// A nil decoder removes the registration.
+func RegisterBodyDecoder(mediaType string, decoder BodyDecoder) {
+	mediaType = strings.ToLower(mediaType)
+	bodyDecodersMu.Lock()
+	defer bodyDecodersMu.Unlock()
+	if decoder == nil {
+		delete(bodyDecoders, mediaType)
+		return
+	}
+	bodyDecoders[mediaType] = decoder
+}
+
+// lookupBodyDecoder returns the decoder for mediaType. Structured syntax
+// suffixes such as application/problem+json fall back to the JSON and XML decoders.
+func lookupBodyDecoder(mediaType string) (BodyDecoder, bool) {
+	bodyDecodersMu.RLock()
+	decoder, ok := bodyDecoders[mediaType]
+	bodyDecodersMu.RUnlock()
+	if ok {
+		return decoder, true
+	}
+
+	switch {
+	case strings.HasSuffix(mediaType, "+json"):
+		return lookupBodyDecoder("application/json")
+	case strings.HasSuffix(mediaType, "+xml"):
+		return lookupBodyDecoder("application/xml")
+	}
+	return nil, false
+}
+
+// BindBody decodes the request body into dest using the decoder registered
+// for its Content-Type and validates the result with Validate.
+// Requests without a Content-Type are decoded as JSON.
+// An unknown Content-Type produces a 415 Unsupported Media Type error.
+func (c *Context) BindBody(dest any) error {
+	if err := c.decodeBody(dest); err != nil {
+		return err
+	}
+	return Validate(dest)
+}
+
+// decodeBody decodes the request body into dest without validating it.
+func (c *Context) decodeBody(dest any) error {
+	mediaType := requestMediaType(c.Request)
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+
+	decoder, ok := lookupBodyDecoder(mediaType)
+	if !ok {
+		return &HTTPError{
+			Code:    StatusUnsupportedMediaType,
+			Message: fmt.Sprintf("unsupported media type %s", mediaType),
+			Err:     ErrUnsupportedMediaType,
+		}
+	}
+	return decoder(c, dest)
+}
+
+// BindBody decodes the request body into a specific type based on its
+// Content-Type: JSON, XML, URL-encoded forms and multipart forms are
+// supported out of the box, and RegisterBodyDecoder adds more.
+// Form bodies fill fields by their `form` tags; multipart file fields use
+// *multipart.FileHeader or []*multipart.FileHeader.
+// Usage: user, err := vayu.BindBody[User](c)
+func BindBody[T any](c *Context) (T, error) {
+	var result T
+	err := c.BindBody(&result)
+	return result, err
+}
+
+// MustBindBody decodes the request body into a specific type and panics if
+// decoding fails.
+// Usage: user := vayu.MustBindBody[User](c)
+func MustBindBody[T any](c *Context) T {
+	result, err := BindBody[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// hasBody reports whether the request carries a body.
+func hasBody(r *http.Request) bool {
+	return r.Body != nil && r.Body != http.NoBody
+}
+
+// decodeJSON is the BodyDecoder for application/json.
+func decodeJSON(c *Context, dest any) error {
+	if err := c.readJSON(dest); err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// decodeXML is the BodyDecoder for application/xml and text/xml. Bodies
+// are capped like JSON ones; see JSONBindingConfig.MaxBodyBytes.
+func decodeXML(c *Context, dest any) error {
+	if !hasBody(c.Request) {
+		return nil
+	}
+	defer c.Request.Body.Close()
+
+	body, limit, err := c.limitedBody()
+	if err != nil {
+		return err
+	}
+	err = xml.NewDecoder(body).Decode(dest)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return bodyTooLarge(limit, err)
+	}
+	return &BindingError{Errors: []*FieldError{{Source: "xml", Err: err}}}
+}
+
+// decodeForm is the BodyDecoder for URL-encoded and multipart forms.
+func decodeForm(c *Context, dest any) error {
+	val := reflect.ValueOf(dest)
+	if val.Kind() != reflect.Pointer || val.IsNil() || val.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("form binding requires a pointer to a struct, got %T", dest)
+	}
+	val = val.Elem()
+
+	src, err := formSource(c)
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return err
+	} else if err != nil {
+		return &BindingError{Errors: []*FieldError{{Source: "form", Err: err}}}
+	}
+
+	_, errs := bindFields(val, []tagSource{src})
+	errs = append(errs, bindFormFiles(c.Request, val)...)
+	if len(errs) > 0 {
+		return &BindingError{Errors: errs}
+	}
+	return nil
+}
+
+var (
+	fileHeaderType  = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType = reflect.TypeOf([]*multipart.FileHeader(nil))
+)
+
+// isFileField reports whether a field receives uploaded files rather than
+// a form value.
+func isFileField(t reflect.Type) bool {
+	return t == fileHeaderType || t == fileHeadersType
+}
+
+// bindFormFiles sets the *multipart.FileHeader and []*multipart.FileHeader
+// fields of val from the parsed multipart form.
+func bindFormFiles(r *http.Request, val reflect.Value) []*FieldError {
+	var files map[string][]*multipart.FileHeader
+	if r.MultipartForm != nil {
+		files = r.MultipartForm.File
+	}
+
+	var errs []*FieldError
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		key := field.Tag.Get("form")
+		if key == "" || !isFileField(field.Type) {
+			continue
+		}
+
+		headers := files[key]
+		if len(headers) == 0 {
+			if field.Tag.Get("required") == "true" {
+				errs = append(errs, &FieldError{Field: field.Name, Source: "form", Key: key, Err: ErrFieldMissing})
+			}
+			continue
+		}
+
+		fieldValue := val.Field(i)
+		if !fieldValue.CanSet() {
+			errs = append(errs, &FieldError{Field: field.Name, Source: "form", Key: key,
+				Err: fmt.Errorf("field %s cannot be set (is it unexported?)", field.Name)})
+			continue
+		}
+		if field.Type == fileHeaderType {
+			fieldValue.Set(reflect.ValueOf(headers[0]))
+		} else {
+			fieldValue.Set(reflect.ValueOf(headers))
+		}
+	}
+	return errs
+}
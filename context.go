@@ -4,13 +4,24 @@ package vayu
 import (
 	"context"
 	"encoding/json"
-	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
+	"slices"
+	"sort"
+	"time"
 )
 
 // Context represents the context of an HTTP request.
 // It encapsulates request and response objects, parameters, and control flow.
+//
+// Context implements context.Context by delegating to Ctx, so it can be
+// passed directly to database drivers, HTTP clients and other APIs that
+// take a context. Use SetContext to replace Ctx; it keeps Request in sync.
+//
+// Contexts are pooled and reused once the handler chain returns, so a
+// Context (including its Params map) must not be retained or used from
+// another goroutine after the handler returns; use Copy for that.
 type Context struct {
 	Writer  *ResponseWriter
 	Request *http.Request
@@ -20,6 +31,263 @@ type Context struct {
 
 	// Custom data store for request-scoped data with type information
 	store map[string]any
+
+	app    *App
+	route  string
+	logger *slog.Logger
+
+	requestID       string
+	requestIDHeader string
+
+	// jsonBinding is the route-level JSON binding configuration, if any
+	jsonBinding *JSONBindingConfig
+
+	// session is set by the Sessions middleware
+	session *Session
+
+	// Pooled state reused across requests
+	writer   ResponseWriter
+	handlers []HandlerFunc
+	index    int
+	nextFunc NextFunc
+
+	// chains holds the outer chains that WithMiddleware sub-chains resume
+	chains []chainFrame
+
+	// cancels holds the cancel functions of open WithTimeout scopes
+	cancels []context.CancelFunc
+}
+
+// newContext allocates a Context for the app's pool.
+func newContext(app *App) *Context {
+	c := &Context{
+		app:    app,
+		Params: make(map[string]string),
+	}
+	// Bind the method value once so calling next never allocates
+	c.nextFunc = c.next
+	return c
+}
+
+// reset prepares a pooled context for a new request, cancelling any
+// deadline scopes left open by the previous one.
+func (c *Context) reset(w http.ResponseWriter, r *http.Request) {
+	for _, cancel := range c.cancels {
+		cancel()
+	}
+	clear(c.cancels)
+	c.cancels = c.cancels[:0]
+
+	c.writer = ResponseWriter{ResponseWriter: w}
+	c.Writer = &c.writer
+	c.Request = r
+	c.Ctx = nil
+	if r != nil {
+		c.Ctx = r.Context()
+	}
+	if c.Params == nil {
+		c.Params = make(map[string]string)
+	} else {
+		clear(c.Params)
+	}
+	c.Stopped = false
+	clear(c.store)
+	c.route = ""
+	c.logger = nil
+	c.requestID = ""
+	c.requestIDHeader = ""
+	c.jsonBinding = nil
+	c.session = nil
+	c.handlers = nil
+	c.index = -1
+	clear(c.chains)
+	c.chains = c.chains[:0]
+}
+
+// fork returns a copy of c that continues the handler chain from the current
+// position on another goroutine, writing to w and running under ctx. The
+// fork owns its own Params, store and writer so it never races with c.
+func (c *Context) fork(ctx context.Context, w http.ResponseWriter) *Context {
+	fc := c.Copy()
+	fc.writer = ResponseWriter{ResponseWriter: w}
+	fc.handlers = c.handlers
+	fc.index = c.index
+	fc.chains = slices.Clone(c.chains)
+	fc.SetContext(ctx)
+	return fc
+}
+
+// join copies the state a finished fork produced back into c.
+func (c *Context) join(fc *Context) {
+	for _, cancel := range fc.cancels {
+		cancel()
+	}
+	c.Stopped = fc.Stopped
+	c.handlers = fc.handlers
+	c.index = fc.index
+	c.chains = fc.chains
+	c.store = fc.store
+	c.logger = fc.logger
+	c.requestID = fc.requestID
+	c.requestIDHeader = fc.requestIDHeader
+	c.jsonBinding = fc.jsonBinding
+	c.session = fc.session
+}
+
+// SetContext replaces the request's context.Context. Both Ctx and the
+// context of Request are updated, so code reading either sees the same
+// deadline, cancellation and values.
+func (c *Context) SetContext(ctx context.Context) {
+	c.Ctx = ctx
+	if c.Request != nil && c.Request.Context() != ctx {
+		c.Request = c.Request.WithContext(ctx)
+	}
+}
+
+// baseContext returns the canonical context.Context of the request.
+func (c *Context) baseContext() context.Context {
+	if c.Ctx != nil {
+		return c.Ctx
+	}
+	if c.Request != nil {
+		return c.Request.Context()
+	}
+	return context.Background()
+}
+
+// Deadline implements context.Context.
+func (c *Context) Deadline() (time.Time, bool) {
+	return c.baseContext().Deadline()
+}
+
+// Done implements context.Context.
+func (c *Context) Done() <-chan struct{} {
+	return c.baseContext().Done()
+}
+
+// Err implements context.Context.
+func (c *Context) Err() error {
+	return c.baseContext().Err()
+}
+
+// Value implements context.Context. String keys are looked up in the
+// request store (see Set) first; all other lookups go to Ctx.
+func (c *Context) Value(key any) any {
+	if k, ok := key.(string); ok {
+		if val, exists := c.Get(k); exists {
+			return val
+		}
+	}
+	return c.baseContext().Value(key)
+}
+
+// next runs the next handler in the chain unless the chain was stopped
+// or the request context is done.
+func (c *Context) next() {
+	c.index++
+	if c.index >= len(c.handlers) || c.Stopped {
+		return
+	}
+
+	// Check if request context is done
+	select {
+	case <-c.Done():
+		// Just write a gateway timeout if the context deadline was exceeded
+		c.Writer.WriteHeader(StatusGatewayTimeout)
+		return
+	default:
+	}
+
+	if c.nextFunc == nil {
+		// Contexts built outside the pool bind lazily
+		c.nextFunc = c.next
+	}
+	c.handlers[c.index](c, c.nextFunc)
+}
+
+// Copy returns a snapshot of the context that stays valid after the handler
+// returns, e.g. for use in a goroutine. The copy shares the request but not
+// the pooled Params and store maps, and it cannot continue the handler chain.
+// Its Writer must not be used once the handler has returned.
+func (c *Context) Copy() *Context {
+	cp := &Context{
+		Request:         c.Request,
+		Params:          make(map[string]string, len(c.Params)),
+		Stopped:         c.Stopped,
+		Ctx:             c.Ctx,
+		app:             c.app,
+		route:           c.route,
+		logger:          c.logger,
+		requestID:       c.requestID,
+		requestIDHeader: c.requestIDHeader,
+		jsonBinding:     c.jsonBinding,
+		session:         c.session,
+		writer:          c.writer,
+		index:           len(c.handlers),
+	}
+	cp.Writer = &cp.writer
+	cp.nextFunc = cp.next
+	for k, v := range c.Params {
+		cp.Params[k] = v
+	}
+	if c.store != nil {
+		cp.store = make(map[string]any, len(c.store))
+		for k, v := range c.store {
+			cp.store[k] = v
+		}
+	}
+	return cp
+}
+
+// RoutePattern returns the pattern of the matched route, e.g. "/users/:id".
+// It is empty when no route matched the request.
+func (c *Context) RoutePattern() string {
+	return c.route
+}
+
+// Logger returns a logger scoped to the current request.
+// Records carry the request ID, method, route pattern and path parameters.
+func (c *Context) Logger() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+
+	base := slog.Default()
+	if c.app != nil && c.app.logger != nil {
+		base = c.app.logger
+	}
+
+	attrs := make([]any, 0, 4)
+	if c.Request != nil {
+		if id := c.RequestID(); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		attrs = append(attrs, slog.String("method", c.Request.Method))
+	}
+	if c.route != "" {
+		attrs = append(attrs, slog.String("route", c.route))
+	}
+	if sc := SpanContextFromContext(c); sc.IsValid() {
+		attrs = append(attrs,
+			slog.String("trace_id", sc.TraceID.String()),
+			slog.String("span_id", sc.SpanID.String()),
+		)
+	}
+	if len(c.Params) > 0 {
+		keys := make([]string, 0, len(c.Params))
+		for k := range c.Params {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		params := make([]any, 0, len(keys))
+		for _, k := range keys {
+			params = append(params, slog.String(k, c.Params[k]))
+		}
+		attrs = append(attrs, slog.Group("params", params...))
+	}
+
+	c.logger = base.With(attrs...)
+	return c.logger
 }
 
 // Query returns the value of the URL query parameter with the given key.
@@ -46,20 +314,21 @@ func (c *Context) Stop() {
 	c.Stopped = true
 }
 
-// BindJSON binds the request body as JSON to the given struct.
+// BindJSON binds the request body as JSON to the given struct, applying
+// the route's or app's JSONBindingConfig, and validates it with Validate.
+// It returns io.EOF for an empty body.
 func (c *Context) BindJSON(dest any) error {
-	if c.Request.Body == nil {
-		return io.EOF
+	if err := c.readJSON(dest); err != nil {
+		return err
 	}
-	defer c.Request.Body.Close()
-	decoder := json.NewDecoder(c.Request.Body)
-	return decoder.Decode(dest)
+	return Validate(dest)
 }
 
 // FormFile returns the uploaded file with the given form field name.
+// Bodies larger than the configured MaxBodyBytes produce an *HTTPError with
+// status 413.
 func (c *Context) FormFile(field string) (multipart.File, *multipart.FileHeader, error) {
-	err := c.Request.ParseMultipartForm(10 << 20) // 10MB
-	if err != nil {
+	if err := c.parseForm(); err != nil {
 		return nil, nil, err
 	}
 	return c.Request.FormFile(field)
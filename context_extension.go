@@ -37,13 +37,36 @@ func (c *Context) Status(code int) *Context {
 	return c
 }
 
-// WithTimeout creates a new context with the given timeout.
-func (c *Context) WithTimeout(timeout time.Duration) {
-	ctx, cancel := context.WithTimeout(c.Ctx, timeout)
-	// Store the cancel function
-	go func() {
-		<-ctx.Done()
-		cancel() // Call cancel when the context is done
-	}()
-	c.Ctx = ctx
+// WithTimeout narrows the request deadline to timeout from now and returns
+// a function that ends the scope: it cancels the derived context and restores
+// the previous one. The new deadline never extends an enclosing one.
+//
+//	restore := c.WithTimeout(2 * time.Second)
+//	defer restore()
+//
+// Scopes that are still open when the request finishes are cancelled
+// automatically, so ignoring the returned function does not leak.
+func (c *Context) WithTimeout(timeout time.Duration) context.CancelFunc {
+	return c.WithDeadline(time.Now().Add(timeout))
+}
+
+// WithDeadline is like WithTimeout but takes an absolute deadline.
+func (c *Context) WithDeadline(deadline time.Time) context.CancelFunc {
+	prev := c.baseContext()
+	ctx, cancel := context.WithDeadline(prev, deadline)
+	c.cancels = append(c.cancels, cancel)
+	c.SetContext(ctx)
+
+	done := false
+	return func() {
+		if done {
+			return
+		}
+		done = true
+		cancel()
+		// Only restore if no inner scope replaced the context since
+		if c.Ctx == ctx {
+			c.SetContext(prev)
+		}
+	}
 }
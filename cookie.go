@@ -0,0 +1,283 @@
+package vayu
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// MinCookieSecretLength is the minimum length of a cookie secret in bytes.
+const MinCookieSecretLength = 32
+
+var (
+	// ErrInvalidCookie is returned when a signed or encrypted cookie is
+	// malformed, was tampered with, or was produced with an unknown key.
+	ErrInvalidCookie = errors.New("cookie is invalid or has been tampered with")
+
+	// ErrNoCookieKeys is returned by the signed and encrypted cookie methods
+	// when the app has no keys; see App.SetCookieKeys.
+	ErrNoCookieKeys = errors.New("no cookie keys configured")
+)
+
+// NewCookie returns a cookie with secure defaults: Path "/", HttpOnly,
+// Secure and SameSite=Lax. Adjust the fields before passing it to SetCookie.
+func NewCookie(name, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// Cookie returns the value of the named request cookie, or
+// http.ErrNoCookie if it is not present.
+func (c *Context) Cookie(name string) (string, error) {
+	cookie, err := c.Request.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return cookie.Value, nil
+}
+
+// SetCookie adds a Set-Cookie header to the response. An empty Path is set
+// to "/" and an unset SameSite to Lax. HttpOnly and Secure are left as
+// given, so a cookie scripts must read can be set; use NewCookie for all
+// the secure defaults. SetSignedCookie and SetEncryptedCookie also turn on
+// HttpOnly and Secure when the cookie sets neither.
+func (c *Context) SetCookie(cookie *http.Cookie) {
+	if cookie.Path == "" || cookie.SameSite == 0 {
+		cp := *cookie
+		if cp.Path == "" {
+			cp.Path = "/"
+		}
+		if cp.SameSite == 0 {
+			cp.SameSite = http.SameSiteLaxMode
+		}
+		cookie = &cp
+	}
+	http.SetCookie(c.Writer, cookie)
+}
+
+// secureCookie returns a copy of cookie with the given value. A cookie that
+// sets neither HttpOnly nor Secure gets both, as from NewCookie; setting
+// either one, such as HttpOnly alone for plain-HTTP development, leaves
+// them as given.
+func secureCookie(cookie *http.Cookie, value string) *http.Cookie {
+	cp := *cookie
+	cp.Value = value
+	if !cp.HttpOnly && !cp.Secure {
+		cp.HttpOnly = true
+		cp.Secure = true
+	}
+	return &cp
+}
+
+// ClearCookie tells the client to delete cookie. The browser only deletes
+// a cookie whose Path and Domain match, so pass the attributes it was set
+// with, e.g. c.ClearCookie(vayu.NewCookie("theme", "")); the value is
+// ignored.
+func (c *Context) ClearCookie(cookie *http.Cookie) {
+	cp := *cookie
+	cp.Value = ""
+	cp.MaxAge = -1
+	cp.Expires = time.Unix(0, 0)
+	c.SetCookie(&cp)
+}
+
+// SignedCookie returns the value of a cookie set with SetSignedCookie.
+// It returns ErrInvalidCookie if the signature does not match.
+func (c *Context) SignedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+	value, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return keys.Verify(name, value)
+}
+
+// SetSignedCookie sets a cookie whose value is signed with HMAC-SHA256, so
+// it can be read by the client but not modified. A cookie that sets
+// neither HttpOnly nor Secure gets both; Path and SameSite default as in
+// SetCookie.
+func (c *Context) SetSignedCookie(cookie *http.Cookie) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+	c.SetCookie(secureCookie(cookie, keys.Sign(cookie.Name, cookie.Value)))
+	return nil
+}
+
+// EncryptedCookie returns the value of a cookie set with SetEncryptedCookie.
+// It returns ErrInvalidCookie if the value cannot be decrypted.
+func (c *Context) EncryptedCookie(name string) (string, error) {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return "", err
+	}
+	value, err := c.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return keys.Decrypt(name, value)
+}
+
+// SetEncryptedCookie sets a cookie whose value is encrypted and
+// authenticated with AES-GCM, so the client can neither read nor modify it.
+// Attributes are applied as for SetSignedCookie.
+func (c *Context) SetEncryptedCookie(cookie *http.Cookie) error {
+	keys, err := c.cookieKeys()
+	if err != nil {
+		return err
+	}
+	c.SetCookie(secureCookie(cookie, keys.Encrypt(cookie.Name, cookie.Value)))
+	return nil
+}
+
+// cookieKeys returns the app's cookie keys.
+func (c *Context) cookieKeys() (*CookieKeys, error) {
+	if c.app == nil || c.app.cookieKeys == nil {
+		return nil, ErrNoCookieKeys
+	}
+	return c.app.cookieKeys, nil
+}
+
+// SetCookieKeys sets the keys used by the signed and encrypted cookie methods.
+func (a *App) SetCookieKeys(keys *CookieKeys) *App {
+	a.cookieKeys = keys
+	return a
+}
+
+// CookieKeys signs and encrypts cookie values. The first secret is used for
+// new cookies and every secret is accepted when reading, so secrets can be
+// rotated by prepending a new one and removing the old one once the
+// cookies it produced have expired.
+type CookieKeys struct {
+	signing [][]byte
+	aeads   []cipher.AEAD
+}
+
+// NewCookieKeys creates CookieKeys from one or more secrets of at least
+// MinCookieSecretLength random bytes, newest first. Separate signing and
+// encryption keys are derived from each secret.
+func NewCookieKeys(secrets ...[]byte) (*CookieKeys, error) {
+	if len(secrets) == 0 {
+		return nil, errors.New("at least one cookie secret is required")
+	}
+
+	keys := &CookieKeys{}
+	for i, secret := range secrets {
+		if len(secret) < MinCookieSecretLength {
+			return nil, fmt.Errorf("cookie secret %d is %d bytes; at least %d are required", i, len(secret), MinCookieSecretLength)
+		}
+		keys.signing = append(keys.signing, deriveKey(secret, "vayu cookie signing"))
+
+		block, err := aes.NewCipher(deriveKey(secret, "vayu cookie encryption"))
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keys.aeads = append(keys.aeads, aead)
+	}
+	return keys, nil
+}
+
+// MustNewCookieKeys is like NewCookieKeys but panics on error.
+func MustNewCookieKeys(secrets ...[]byte) *CookieKeys {
+	keys, err := NewCookieKeys(secrets...)
+	if err != nil {
+		panic(err)
+	}
+	return keys
+}
+
+// deriveKey derives a 32-byte key for one purpose from a secret.
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+var cookieEncoding = base64.RawURLEncoding
+
+// Sign returns value signed for the cookie called name. The cookie name is
+// part of the signature, so a value cannot be moved to another cookie.
+func (k *CookieKeys) Sign(name, value string) string {
+	encoded := cookieEncoding.EncodeToString([]byte(value))
+	return encoded + "." + cookieEncoding.EncodeToString(cookieMAC(k.signing[0], name, encoded))
+}
+
+// Verify checks a value produced by Sign and returns the original value.
+func (k *CookieKeys) Verify(name, signed string) (string, error) {
+	encoded, sig, ok := strings.Cut(signed, ".")
+	if !ok {
+		return "", ErrInvalidCookie
+	}
+	mac, err := cookieEncoding.DecodeString(sig)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, key := range k.signing {
+		if hmac.Equal(mac, cookieMAC(key, name, encoded)) {
+			value, err := cookieEncoding.DecodeString(encoded)
+			if err != nil {
+				return "", ErrInvalidCookie
+			}
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
+
+// cookieMAC computes the HMAC-SHA256 of a cookie name and encoded value.
+func cookieMAC(key []byte, name, encoded string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// Encrypt returns value encrypted for the cookie called name. The cookie
+// name is authenticated with the value, so it cannot be moved to another cookie.
+func (k *CookieKeys) Encrypt(name, value string) string {
+	aead := k.aeads[0]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	readRandom(nonce)
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return cookieEncoding.EncodeToString(sealed)
+}
+
+// Decrypt opens a value produced by Encrypt and returns the original value.
+func (k *CookieKeys) Decrypt(name, encrypted string) (string, error) {
+	sealed, err := cookieEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", ErrInvalidCookie
+	}
+	for _, aead := range k.aeads {
+		if len(sealed) < aead.NonceSize()+aead.Overhead() {
+			return "", ErrInvalidCookie
+		}
+		nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if value, err := aead.Open(nil, nonce, ciphertext, []byte(name)); err == nil {
+			return string(value), nil
+		}
+	}
+	return "", ErrInvalidCookie
+}
@@ -1,15 +1,20 @@
 package vayu
 
-import "fmt"
-
 func Recovery() HandlerFunc {
 	return func(c *Context, next NextFunc) {
 		defer func() {
 			if r := recover(); r != nil {
-				fmt.Printf("❌ Panic recovered: %v\n", r)
-				err := c.JSON(500, map[string]string{"error": "Internal Server Error"})
-				if err != nil {
-					fmt.Printf("❌ Error sending JSON response: %v\n", err)
+				body := map[string]any{"error": "Internal Server Error"}
+				code := StatusInternalServerError
+				err := recoveredError(r)
+				if status, msg := errorStatus(err); status < StatusInternalServerError {
+					code, body = status, errorBody(err, status, msg)
+					c.Logger().Warn("request error", "status", code, "error", err)
+				} else {
+					c.Logger().Error("panic recovered", "error", err)
+				}
+				if err := c.JSON(code, body); err != nil {
+					c.Logger().Error("error sending JSON response", "error", err)
 				}
 			}
 		}()
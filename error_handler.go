@@ -1,39 +1,124 @@
 package vayu
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os"
+	"net/http"
 	"runtime/debug"
-	"strings"
 )
 
 // ErrorHandler represents a function that handles errors in middleware or handlers
 type ErrorHandler func(c *Context, err error)
 
-// SilentMode controls whether panic logs are output
-// Set to true to suppress panic logs (useful in tests)
-// This can be controlled via linker flags: -ldflags="-X 'github.com/kaushiksamanta/vayu.SilentMode=true'"
-var SilentMode string
+// StatusCoder is implemented by errors that map to a specific HTTP status.
+// The default error handler responds with that status instead of 500.
+type StatusCoder interface {
+	StatusCode() int
+}
+
+// ErrorDetailer is implemented by errors that carry machine-readable details,
+// such as the field list of a ValidationError. The default error handler
+// includes them in 4xx responses under "details".
+type ErrorDetailer interface {
+	ErrorDetails() any
+}
+
+// HTTPError is an error that carries the HTTP status code it should produce.
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+// NewHTTPError creates an HTTPError with the given status code and message.
+func NewHTTPError(code int, message string) *HTTPError {
+	return &HTTPError{Code: code, Message: message}
+}
+
+// Error implements the error interface.
+func (e *HTTPError) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	if e.Err != nil {
+		return e.Err.Error()
+	}
+	return http.StatusText(e.Code)
+}
+
+// StatusCode implements StatusCoder.
+func (e *HTTPError) StatusCode() int {
+	return e.Code
+}
 
-// isSilentMode returns true if SilentMode is set to "true" or if we're running tests
-func isSilentMode() bool {
-	return SilentMode == "true" || (SilentMode == "" && strings.Contains(os.Args[0], "test"))
+// Unwrap returns the underlying error.
+func (e *HTTPError) Unwrap() error {
+	return e.Err
 }
 
-// LogPanic logs panic information if not in silent mode
-func LogPanic(err error, stackTrace []byte) {
-	if !isSilentMode() {
-		log.Printf("Panic recovered: %v\n%s", err, stackTrace)
+// errorStatus returns the status code and client-facing message for err.
+// Messages of server errors are never exposed.
+func errorStatus(err error) (int, string) {
+	var sc StatusCoder
+	if errors.As(err, &sc) {
+		if code := sc.StatusCode(); code >= 400 && code < 500 {
+			return code, err.Error()
+		}
 	}
+	return StatusInternalServerError, "An unexpected error occurred"
 }
 
-// DefaultErrorHandler is the default error handler
+// errorBody builds the JSON body sent for err with the given status.
+func errorBody(err error, code int, message string) map[string]any {
+	body := map[string]any{"error": message}
+	var detailer ErrorDetailer
+	if code < StatusInternalServerError && errors.As(err, &detailer) {
+		if details := detailer.ErrorDetails(); details != nil {
+			body["details"] = details
+		}
+	}
+	return body
+}
+
+// DefaultErrorHandler is the default error handler.
+// Errors implementing StatusCoder with a 4xx code produce that status and
+// their message, plus the details of an ErrorDetailer; everything else
+// produces a generic 500.
 var DefaultErrorHandler = func(c *Context, err error) {
-	log.Printf("Error: %v", err)
-	if !c.Writer.Written() {
-		c.InternalServerError("An unexpected error occurred")
+	code, message := errorStatus(err)
+	if code >= StatusInternalServerError {
+		c.Logger().Error("unhandled error", "error", err)
+	} else {
+		c.Logger().Warn("request error", "status", code, "error", err)
+	}
+	if c.Writer.Written() {
+		return
+	}
+
+	body := errorBody(err, code, message)
+	if id := c.RequestID(); id != "" {
+		body["request_id"] = id
+	}
+	c.JSON(code, body)
+}
+
+// recoveredError converts a recovered panic value into an error.
+func recoveredError(r any) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
+
+// handlePanic logs a recovered panic and passes it to errorHandler. Client
+// errors, such as a bad parameter from MustPathParam, are logged by the
+// error handler without a stack.
+func handlePanic(c *Context, r any, errorHandler ErrorHandler) {
+	err := recoveredError(r)
+	if code, _ := errorStatus(err); code >= StatusInternalServerError {
+		logPanic(c, err, debug.Stack())
 	}
+	errorHandler(c, err)
 }
 
 // WithErrorHandling wraps a handler with error handling
@@ -45,14 +130,7 @@ func WithErrorHandling(handler HandlerFunc, errorHandler ErrorHandler) HandlerFu
 	return func(c *Context, next NextFunc) {
 		defer func() {
 			if r := recover(); r != nil {
-				stackTrace := debug.Stack()
-				err, ok := r.(error)
-				if !ok {
-					err = fmt.Errorf("%v", r)
-				}
-
-				LogPanic(err, stackTrace)
-				errorHandler(c, err)
+				handlePanic(c, r, errorHandler)
 			}
 		}()
 
@@ -69,14 +147,7 @@ func ErrorHandlerMiddleware(errorHandler ErrorHandler) HandlerFunc {
 	return func(c *Context, next NextFunc) {
 		defer func() {
 			if r := recover(); r != nil {
-				stackTrace := debug.Stack()
-				err, ok := r.(error)
-				if !ok {
-					err = fmt.Errorf("%v", r)
-				}
-
-				LogPanic(err, stackTrace)
-				errorHandler(c, err)
+				handlePanic(c, r, errorHandler)
 			}
 		}()
 
@@ -0,0 +1,173 @@
+package vayu
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// File sends the file at path. The Content-Type is derived from the
+// extension or the content, and Last-Modified and ETag headers are set
+// from the file's modification time and size. Range, If-Range,
+// If-Modified-Since, If-None-Match and HEAD requests are handled as by
+// http.ServeContent.
+//
+// path is opened as is; clean and confine paths built from request data.
+// A missing file or a directory produces an *HTTPError with status 404 and
+// an unreadable file one with status 403; nothing is written for either.
+func (c *Context) File(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fileError(err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fileError(err)
+	}
+	if info.IsDir() {
+		return fileError(fs.ErrNotExist)
+	}
+
+	h := c.Writer.Header()
+	if h.Get("ETag") == "" {
+		h.Set("ETag", fileETag(info.ModTime(), info.Size()))
+	}
+	http.ServeContent(c.Writer, c.Request, info.Name(), info.ModTime(), f)
+	return nil
+}
+
+// Attachment sends the file at path like File and asks the browser to
+// download it as filename. An empty filename uses the file's base name.
+func (c *Context) Attachment(path, filename string) error {
+	return c.serveDisposition("attachment", path, filename)
+}
+
+// Inline sends the file at path like File and asks the browser to display
+// it, suggesting filename if the user saves it. An empty filename uses the
+// file's base name.
+func (c *Context) Inline(path, filename string) error {
+	return c.serveDisposition("inline", path, filename)
+}
+
+// serveDisposition sends a file with a Content-Disposition header.
+func (c *Context) serveDisposition(disposition, path, filename string) error {
+	if filename == "" {
+		filename = filepath.Base(path)
+	}
+	c.Writer.Header().Set("Content-Disposition", contentDisposition(disposition, filename))
+	if err := c.File(path); err != nil {
+		c.Writer.Header().Del("Content-Disposition")
+		return err
+	}
+	return nil
+}
+
+// Stream sends the content of r with the given Content-Type, which is
+// detected from the content when empty. If r is an io.ReadSeeker, such as
+// an *os.File or *bytes.Reader, Range, If-Range, conditional and HEAD
+// requests are handled as by http.ServeContent, using any Last-Modified or
+// ETag header already set on the response. Other readers are copied to the
+// client with a 200 OK. The caller closes r.
+func (c *Context) Stream(contentType string, r io.Reader) error {
+	h := c.Writer.Header()
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+
+	if rs, ok := r.(io.ReadSeeker); ok {
+		var modtime time.Time
+		if lastModified := h.Get("Last-Modified"); lastModified != "" {
+			modtime, _ = http.ParseTime(lastModified)
+		}
+		http.ServeContent(c.Writer, c.Request, "", modtime, rs)
+		return nil
+	}
+
+	c.Writer.WriteHeader(StatusOK)
+	if c.Request.Method == http.MethodHead {
+		return nil
+	}
+	_, err := io.Copy(c.Writer, r)
+	return err
+}
+
+// fileError converts an error opening a file into an *HTTPError.
+func fileError(err error) error {
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return &HTTPError{Code: StatusNotFound, Message: "file not found", Err: err}
+	case errors.Is(err, fs.ErrPermission):
+		return &HTTPError{Code: StatusForbidden, Message: "file not accessible", Err: err}
+	}
+	return err
+}
+
+// fileETag builds a strong validator from a file's modification time and
+// size, as common web servers do.
+func fileETag(modtime time.Time, size int64) string {
+	return fmt.Sprintf(`"%s-%s"`, strconv.FormatInt(modtime.UnixNano(), 36), strconv.FormatInt(size, 36))
+}
+
+// contentDisposition formats a Content-Disposition header for filename.
+// Names that are not plain ASCII get an RFC 5987 filename* parameter along
+// with an ASCII fallback for older clients.
+func contentDisposition(disposition, filename string) string {
+	var fallback strings.Builder
+	ascii := true
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\':
+			fallback.WriteByte('\\')
+			fallback.WriteRune(r)
+		case r < 0x20 || r == 0x7f:
+			fallback.WriteByte('_')
+		case r > 0x7e:
+			ascii = false
+			fallback.WriteByte('_')
+		default:
+			fallback.WriteRune(r)
+		}
+	}
+
+	header := disposition + `; filename="` + fallback.String() + `"`
+	if !ascii {
+		header += "; filename*=UTF-8''" + encodeRFC5987(filename)
+	}
+	return header
+}
+
+// encodeRFC5987 percent-encodes s as an RFC 5987 ext-value, leaving only
+// attr-char bytes unescaped.
+func encodeRFC5987(s string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if isAttrChar(ch) {
+			b.WriteByte(ch)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[ch>>4])
+		b.WriteByte(hex[ch&0x0f])
+	}
+	return b.String()
+}
+
+// isAttrChar reports whether ch is an RFC 5987 attr-char.
+func isAttrChar(ch byte) bool {
+	switch {
+	case 'a' <= ch && ch <= 'z', 'A' <= ch && ch <= 'Z', '0' <= ch && ch <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", ch) >= 0
+}
@@ -1,8 +1,10 @@
 package vayu
 
 import (
+	"encoding"
 	"encoding/json"
 	"fmt"
+	"iter"
 	"net/url"
 	"reflect"
 	"strconv"
@@ -19,6 +21,62 @@ func JSONResponse[T any](c *Context, code int, obj T) error {
 	return c.JSON(code, obj)
 }
 
+// XMLResponse sends an XML response with the given status code and typed object.
+func XMLResponse[T any](c *Context, code int, obj T) error {
+	return c.XML(code, obj)
+}
+
+// YAMLResponse sends a YAML response with the given status code and typed object.
+func YAMLResponse[T any](c *Context, code int, obj T) error {
+	return c.YAML(code, obj)
+}
+
+// MsgPackResponse sends a MessagePack response with the given status code and typed object.
+func MsgPackResponse[T any](c *Context, code int, obj T) error {
+	return c.MsgPack(code, obj)
+}
+
+// CSVResponse streams typed rows as CSV, with columns named by `csv` tags.
+// Usage: err := vayu.CSVResponse(c, vayu.StatusOK, orders)
+func CSVResponse[T any](c *Context, code int, rows []T) error {
+	return c.CSV(code, rows)
+}
+
+// NDJSON streams the values of seq as newline-delimited JSON, flushing each
+// line to the client. It stops early, returning the context's error, when
+// the request is cancelled or its deadline passes. App.RequestTimeout
+// (30 seconds by default) applies to streams too, so long-lived streams
+// need a larger timeout or none.
+// Usage: err := vayu.NDJSON(c, vayu.StatusOK, slices.Values(events))
+func NDJSON[T any](c *Context, code int, seq iter.Seq[T]) error {
+	return c.ndjson(code, func(yield func(any) bool) {
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+		}
+	})
+}
+
+// NDJSONChannel streams values received from ch as newline-delimited JSON
+// until ch is closed or the request is cancelled, including by
+// App.RequestTimeout as for NDJSON.
+// Usage: err := vayu.NDJSONChannel(c, vayu.StatusOK, events)
+func NDJSONChannel[T any](c *Context, code int, ch <-chan T) error {
+	return c.ndjson(code, func(yield func(any) bool) {
+		for {
+			select {
+			case v, ok := <-ch:
+				if !ok || !yield(v) {
+					return
+				}
+			case <-c.Done():
+				return
+			}
+		}
+	})
+}
+
 // BindJSONBody binds the request body as JSON to a specific type.
 // This provides compile-time type safety through generics.
 // Usage: user, err := vayu.BindJSONBody[User](c)
@@ -136,66 +194,157 @@ func MustBindParamJSON[T any](c *Context, paramName string) T {
 // Usage: params := vayu.BindQueryParams[SearchParams](c)
 // Define your struct with `query` tags: type SearchParams struct { Term string `query:"q"` }
 func BindQueryParams[T any](c *Context) (T, error) {
-	var result T
-	val := reflect.ValueOf(&result).Elem()
-	typ := val.Type()
+	return bindTagged[T](querySource(c))
+}
 
-	errs := make([]string, 0)
-	processed := false
+// MustBindQueryParams binds query parameters to a struct and panics if binding fails.
+// Usage: params := vayu.MustBindQueryParams[SearchParams](c)
+func MustBindQueryParams[T any](c *Context) T {
+	result, err := BindQueryParams[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
 
-	// Process each field with a `query` tag
-	for i := 0; i < typ.NumField(); i++ {
-		field := typ.Field(i)
-		queryTag := field.Tag.Get("query")
-		if queryTag == "" {
-			continue
-		}
+// BindPathParams binds path parameters to a struct based on struct tags.
+// This provides compile-time type safety through generics.
+// Every tagged parameter must be present in the matched route.
+// Usage: params := vayu.BindPathParams[UserRoute](c)
+// Define your struct with `path` tags: type UserRoute struct { ID int `path:"id"` }
+func BindPathParams[T any](c *Context) (T, error) {
+	return bindTagged[T](pathSource(c))
+}
 
-		processed = true
-		queryValue := c.Query(queryTag)
-		if queryValue == "" {
-			// Check if the field is required
-			if requiredTag, ok := field.Tag.Lookup("required"); ok && requiredTag == "true" {
-				errs = append(errs, fmt.Sprintf("required query parameter %s missing", queryTag))
-			}
-			continue
-		}
+// MustBindPathParams binds path parameters to a struct and panics if binding fails.
+// Usage: params := vayu.MustBindPathParams[UserRoute](c)
+func MustBindPathParams[T any](c *Context) T {
+	result, err := BindPathParams[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
 
-		fieldValue := val.Field(i)
-		if !fieldValue.CanSet() {
-			errs = append(errs, fmt.Sprintf("field %s cannot be set (is it unexported?)", field.Name))
-			continue
-		}
+// BindHeaders binds request headers to a struct based on struct tags.
+// This provides compile-time type safety through generics.
+// Slice fields collect the values of repeated headers.
+// Usage: meta := vayu.BindHeaders[RequestMeta](c)
+// Define your struct with `header` tags: type RequestMeta struct { Tenant string `header:"X-Tenant-ID"` }
+func BindHeaders[T any](c *Context) (T, error) {
+	return bindTagged[T](headerSource(c))
+}
 
-		// Convert the string value to the appropriate field type
-		if err := setFieldFromString(fieldValue, queryValue); err != nil {
-			errs = append(errs, fmt.Sprintf("parameter %s: %v", queryTag, err))
-		}
+// MustBindHeaders binds request headers to a struct and panics if binding fails.
+// Usage: meta := vayu.MustBindHeaders[RequestMeta](c)
+func MustBindHeaders[T any](c *Context) T {
+	result, err := BindHeaders[T](c)
+	if err != nil {
+		panic(err)
 	}
+	return result
+}
 
-	if !processed {
-		return result, fmt.Errorf("no fields with 'query' tag found in type %s", typ.Name())
+// BindCookies binds request cookies to a struct based on struct tags.
+// This provides compile-time type safety through generics.
+// Usage: session := vayu.BindCookies[SessionCookies](c)
+// Define your struct with `cookie` tags: type SessionCookies struct { ID string `cookie:"session"` }
+func BindCookies[T any](c *Context) (T, error) {
+	return bindTagged[T](cookieSource(c))
+}
+
+// MustBindCookies binds request cookies to a struct and panics if binding fails.
+// Usage: session := vayu.MustBindCookies[SessionCookies](c)
+func MustBindCookies[T any](c *Context) T {
+	result, err := BindCookies[T](c)
+	if err != nil {
+		panic(err)
 	}
+	return result
+}
+
+// bindTagged creates a T and sets every field carrying src.tag from the
+// value src.lookup returns for the tag's key, converting it with
+// setFieldFromString. Field errors are returned together as a *BindingError,
+// and a successfully bound value is checked with Validate.
+func bindTagged[T any](src tagSource) (T, error) {
+	var result T
+	val := reflect.ValueOf(&result).Elem()
 
+	processed, errs := bindFields(val, []tagSource{src})
+	if !processed {
+		return result, fmt.Errorf("no fields with '%s' tag found in type %s", src.tag, val.Type().Name())
+	}
 	if len(errs) > 0 {
-		return result, fmt.Errorf("binding query parameters: %s", strings.Join(errs, "; "))
+		return result, &BindingError{Errors: errs}
 	}
+	return result, Validate(&result)
+}
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	timeType            = reflect.TypeOf(time.Time{})
+)
 
-	return result, nil
+// isTextUnmarshaler reports whether values of type t parse themselves
+// through encoding.TextUnmarshaler.
+func isTextUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
 }
 
-// MustBindQueryParams binds query parameters to a struct and panics if binding fails.
-// Usage: params := vayu.MustBindQueryParams[SearchParams](c)
-func MustBindQueryParams[T any](c *Context) T {
-	result, err := BindQueryParams[T](c)
-	if err != nil {
-		panic(err)
+// setFieldFromValues sets a field from one or more raw values. Slices
+// receive every value, with comma-separated values split into elements;
+// other types use the first value. format is the layout for time.Time.
+func setFieldFromValues(fieldValue reflect.Value, values []string, format string) error {
+	if fieldValue.Kind() == reflect.Pointer {
+		ptr := reflect.New(fieldValue.Type().Elem())
+		if err := setFieldFromValues(ptr.Elem(), values, format); err != nil {
+			return err
+		}
+		fieldValue.Set(ptr)
+		return nil
 	}
-	return result
+
+	if fieldValue.Kind() != reflect.Slice || isTextUnmarshaler(fieldValue.Type()) {
+		return setFieldFromString(fieldValue, values[0], format)
+	}
+
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			parts = append(parts, strings.TrimSpace(part))
+		}
+	}
+
+	slice := reflect.MakeSlice(fieldValue.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := setFieldFromString(slice.Index(i), part, format); err != nil {
+			return err
+		}
+	}
+	fieldValue.Set(slice)
+	return nil
 }
 
-// setFieldFromString converts a string value to the appropriate field type and sets it
-func setFieldFromString(fieldValue reflect.Value, value string) error {
+// setFieldFromString converts a string value to the appropriate field type and sets it.
+// format is the layout used for time.Time values; RFC 3339 is used when it is empty.
+func setFieldFromString(fieldValue reflect.Value, value string, format string) error {
+	if fieldValue.Type() == timeType && format != "" {
+		v, err := time.Parse(format, value)
+		if err != nil {
+			return fmt.Errorf("cannot convert '%s' to time with format '%s': %w", value, format, err)
+		}
+		fieldValue.Set(reflect.ValueOf(v))
+		return nil
+	}
+
+	if isTextUnmarshaler(fieldValue.Type()) {
+		if err := fieldValue.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value)); err != nil {
+			return fmt.Errorf("cannot convert '%s' to %s: %w", value, fieldValue.Type(), err)
+		}
+		return nil
+	}
+
 	switch fieldValue.Kind() {
 	case reflect.String:
 		fieldValue.SetString(value)
@@ -219,7 +368,7 @@ func setFieldFromString(fieldValue reflect.Value, value string) error {
 			return nil
 		}
 
-		v, err := strconv.ParseInt(value, 10, 64)
+		v, err := strconv.ParseInt(value, 10, fieldValue.Type().Bits())
 		if err != nil {
 			return fmt.Errorf("cannot convert '%s' to int: %w", value, err)
 		}
@@ -227,7 +376,7 @@ func setFieldFromString(fieldValue reflect.Value, value string) error {
 		return nil
 
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		v, err := strconv.ParseUint(value, 10, 64)
+		v, err := strconv.ParseUint(value, 10, fieldValue.Type().Bits())
 		if err != nil {
 			return fmt.Errorf("cannot convert '%s' to uint: %w", value, err)
 		}
@@ -235,53 +384,17 @@ func setFieldFromString(fieldValue reflect.Value, value string) error {
 		return nil
 
 	case reflect.Float32, reflect.Float64:
-		v, err := strconv.ParseFloat(value, 64)
+		v, err := strconv.ParseFloat(value, fieldValue.Type().Bits())
 		if err != nil {
 			return fmt.Errorf("cannot convert '%s' to float: %w", value, err)
 		}
 		fieldValue.SetFloat(v)
 		return nil
 
-	case reflect.Slice:
-		// Handle comma-separated values for slices
-		values := strings.Split(value, ",")
-		sliceType := fieldValue.Type().Elem().Kind()
-		slice := reflect.MakeSlice(fieldValue.Type(), len(values), len(values))
-
-		for i, v := range values {
-			elemValue := slice.Index(i)
-			v = strings.TrimSpace(v)
-
-			switch sliceType {
-			case reflect.String:
-				elemValue.SetString(v)
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				intVal, err := strconv.ParseInt(v, 10, 64)
-				if err != nil {
-					return fmt.Errorf("cannot convert '%s' to int in slice: %w", v, err)
-				}
-				elemValue.SetInt(intVal)
-			case reflect.Float32, reflect.Float64:
-				floatVal, err := strconv.ParseFloat(v, 64)
-				if err != nil {
-					return fmt.Errorf("cannot convert '%s' to float in slice: %w", v, err)
-				}
-				elemValue.SetFloat(floatVal)
-			case reflect.Bool:
-				boolVal, err := strconv.ParseBool(v)
-				if err != nil {
-					return fmt.Errorf("cannot convert '%s' to bool in slice: %w", v, err)
-				}
-				elemValue.SetBool(boolVal)
-			default:
-				return fmt.Errorf("unsupported slice element type: %s", sliceType)
-			}
-		}
-
-		fieldValue.Set(slice)
-		return nil
+	case reflect.Pointer, reflect.Slice:
+		return setFieldFromValues(fieldValue, []string{value}, format)
 
 	default:
-		return fmt.Errorf("unsupported field type: %s", fieldValue.Kind())
+		return fmt.Errorf("unsupported field type: %s", fieldValue.Type())
 	}
 }
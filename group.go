@@ -21,10 +21,18 @@ func (g *Group) Use(mw HandlerFunc) {
 	})
 }
 
-func (g *Group) GET(path string, handler HandlerFunc) {
+func (g *Group) GET(path string, handler HandlerFunc) *Group {
 	g.app.GET(g.prefix+path, handler)
+	return g
 }
 
-func (g *Group) POST(path string, handler HandlerFunc) {
+func (g *Group) POST(path string, handler HandlerFunc) *Group {
 	g.app.POST(g.prefix+path, handler)
+	return g
+}
+
+// Name names the most recently registered route; see App.Name.
+func (g *Group) Name(name string) *Group {
+	g.app.Name(name)
+	return g
 }
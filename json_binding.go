@@ -0,0 +1,182 @@
+package vayu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the request body size limit used when
+// JSONBindingConfig.MaxBodyBytes is zero.
+const DefaultMaxBodyBytes int64 = 10 << 20 // 10MB
+
+// JSONBindingConfig controls how JSON request bodies are decoded by
+// BindJSON, BindJSONBody, BindBody and Bind.
+type JSONBindingConfig struct {
+	// MaxBodyBytes limits the size of JSON, XML and form bodies, including
+	// multipart uploads; larger bodies produce a 413 Request Entity Too
+	// Large error. Zero uses DefaultMaxBodyBytes and a negative value
+	// disables the limit.
+	MaxBodyBytes int64
+
+	// DisallowUnknownFields rejects objects with keys that match no field.
+	DisallowUnknownFields bool
+
+	// UseNumber decodes numbers into interface{} values as json.Number
+	// instead of float64.
+	UseNumber bool
+
+	// DisallowTrailingData rejects bodies with anything but whitespace
+	// after the first JSON value.
+	DisallowTrailingData bool
+}
+
+// SetJSONBinding sets the JSON binding configuration for every route.
+// Individual routes can override it with the JSONBinding middleware.
+func (a *App) SetJSONBinding(config JSONBindingConfig) *App {
+	a.jsonBinding = config
+	return a
+}
+
+// JSONBinding returns middleware that applies config to the JSON binding of
+// the rest of the chain, replacing the app's configuration:
+//
+//	app.POST("/import", vayu.WithMiddleware(importHandler, vayu.JSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 100 << 20})))
+func JSONBinding(config JSONBindingConfig) HandlerFunc {
+	return func(c *Context, next NextFunc) {
+		c.jsonBinding = &config
+		next()
+	}
+}
+
+// jsonBindingConfig returns the configuration in effect for the request.
+func (c *Context) jsonBindingConfig() JSONBindingConfig {
+	if c.jsonBinding != nil {
+		return *c.jsonBinding
+	}
+	if c.app != nil {
+		return c.app.jsonBinding
+	}
+	return JSONBindingConfig{}
+}
+
+// readJSON decodes the request body into dest according to the binding
+// configuration. It returns io.EOF for an empty body, an *HTTPError with
+// status 413 for an oversized one and a *BindingError for malformed JSON.
+func (c *Context) readJSON(dest any) error {
+	r := c.Request
+	if !hasBody(r) {
+		return io.EOF
+	}
+	defer r.Body.Close()
+
+	config := c.jsonBindingConfig()
+	body, limit, err := c.limitedBody()
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(body)
+	if config.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	if config.UseNumber {
+		decoder.UseNumber()
+	}
+
+	if err := decoder.Decode(dest); err != nil {
+		if err == io.EOF {
+			return io.EOF
+		}
+		return jsonDecodeError(decoder, err, limit)
+	}
+
+	if config.DisallowTrailingData {
+		offset := decoder.InputOffset()
+		if _, err := decoder.Token(); err != io.EOF {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				return bodyTooLarge(limit, err)
+			}
+			return &BindingError{Errors: []*FieldError{{
+				Source: "json",
+				Offset: offset,
+				Err:    errors.New("unexpected data after top-level value"),
+			}}}
+		}
+	}
+	return nil
+}
+
+// limitedBody returns the request body capped at the configured
+// MaxBodyBytes and the limit in effect, or a 413 error when the declared
+// Content-Length already exceeds it.
+func (c *Context) limitedBody() (io.ReadCloser, int64, error) {
+	r := c.Request
+	limit := c.jsonBindingConfig().MaxBodyBytes
+	if limit == 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	if limit < 0 {
+		return r.Body, limit, nil
+	}
+	if r.ContentLength > limit {
+		return nil, limit, bodyTooLarge(limit, nil)
+	}
+	// Pass the connection's writer so the server learns the limit was hit
+	// and closes the connection instead of draining the rest of the body
+	return http.MaxBytesReader(c.Writer.ResponseWriter, r.Body, limit), limit, nil
+}
+
+// bodyTooLarge returns the 413 error for a body exceeding limit bytes.
+func bodyTooLarge(limit int64, err error) error {
+	return &HTTPError{
+		Code:    StatusRequestEntityTooLarge,
+		Message: fmt.Sprintf("request body too large: limit is %d bytes", limit),
+		Err:     err,
+	}
+}
+
+// jsonDecodeError converts an encoding/json error into a *BindingError
+// naming the offending field and byte offset.
+func jsonDecodeError(decoder *json.Decoder, err error, limit int64) error {
+	var (
+		tooLarge  *http.MaxBytesError
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+
+	fe := &FieldError{Source: "json", Err: err}
+	switch {
+	case errors.As(err, &tooLarge):
+		return bodyTooLarge(limit, err)
+
+	case errors.As(err, &syntaxErr):
+		fe.Offset = syntaxErr.Offset
+		fe.Err = errors.New(strings.TrimPrefix(syntaxErr.Error(), "json: "))
+
+	case errors.As(err, &typeErr):
+		fe.Field = typeErr.Field
+		fe.Key = typeErr.Field
+		fe.Offset = typeErr.Offset
+		fe.Err = fmt.Errorf("cannot convert %s to %s", typeErr.Value, typeErr.Type)
+
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// DisallowUnknownFields reports a plain error with the quoted key and
+		// only after the whole value was read, so there is no useful offset
+		name, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		if unquoteErr == nil {
+			fe.Field = name
+			fe.Key = name
+			fe.Err = errors.New("unknown field")
+		}
+
+	case err == io.ErrUnexpectedEOF:
+		fe.Offset = decoder.InputOffset()
+	}
+	return &BindingError{Errors: []*FieldError{fe}}
+}
@@ -1,15 +1,87 @@
 package vayu
 
 import (
-	"fmt"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
 	"time"
 )
 
+// LevelSilent is a log level above every standard slog level.
+// A logger whose minimum level is LevelSilent discards all records.
+const LevelSilent = slog.Level(12)
+
+// LogLevel sets the minimum level of the logger created by New.
+// Accepted values are "debug", "info", "warn", "error" and "silent".
+// When empty, "info" is used, or "silent" when running under `go test`.
+// This can be controlled via linker flags: -ldflags="-X 'github.com/kaushiksamanta/vayu.LogLevel=debug'"
+var LogLevel string
+
+// defaultLogLevel resolves LogLevel to a slog.Level.
+func defaultLogLevel() slog.Level {
+	switch strings.ToLower(LogLevel) {
+	case "debug":
+		return slog.LevelDebug
+	case "info":
+		return slog.LevelInfo
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	case "silent", "off":
+		return LevelSilent
+	}
+	// Keep test output clean unless a level was requested explicitly
+	if strings.Contains(os.Args[0], "test") {
+		return LevelSilent
+	}
+	return slog.LevelInfo
+}
+
+// Logger returns the structured logger used by the framework internals.
+func (a *App) Logger() *slog.Logger {
+	return a.logger
+}
+
+// SetLogger replaces the application logger.
+// Passing nil restores the default text logger writing to stderr.
+func (a *App) SetLogger(logger *slog.Logger) *App {
+	if logger == nil {
+		logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: a.logLevel}))
+	}
+	a.logger = logger
+	return a
+}
+
+// SetLogLevel changes the minimum level of the default application logger.
+// It has no effect on a logger installed with SetLogger, whose handler
+// decides what is enabled.
+func (a *App) SetLogLevel(level slog.Level) *App {
+	a.logLevel.Set(level)
+	return a
+}
+
+// logPanic logs a recovered panic together with its stack trace.
+func logPanic(c *Context, err error, stackTrace []byte) {
+	logger := c.Logger()
+	if !logger.Enabled(context.Background(), slog.LevelError) {
+		return
+	}
+	logger.Error("panic recovered", "error", err, "stack", string(stackTrace))
+}
+
+// Logger returns middleware that logs every request with its duration
+// through the request-scoped logger.
 func Logger() HandlerFunc {
 	return func(c *Context, next NextFunc) {
 		start := time.Now()
 		next()
-		duration := time.Since(start)
-		fmt.Printf("[%s] %s %s (%s)\n", time.Now().Format(time.RFC3339), c.Request.Method, c.Request.URL.Path, duration)
+		c.Logger().Info("request completed",
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"size", c.Writer.Size(),
+			"duration", time.Since(start),
+		)
 	}
 }
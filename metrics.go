@@ -0,0 +1,303 @@
+package vayu
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MetricsContentType is the Content-Type of the Prometheus text exposition format.
+const MetricsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// DefaultMetricsBuckets are the latency histogram buckets in seconds,
+// matching the Prometheus client defaults.
+var DefaultMetricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// MetricsConfig configures request metrics collection.
+type MetricsConfig struct {
+	// Namespace prefixes every metric name. Defaults to "vayu".
+	Namespace string
+
+	// Buckets are the upper bounds of the latency histogram in seconds.
+	// Defaults to DefaultMetricsBuckets.
+	Buckets []float64
+
+	// SkipPaths lists request paths that are not measured.
+	SkipPaths []string
+}
+
+// requestKey identifies a counter or histogram series.
+type requestKey struct {
+	method string
+	route  string
+	status int
+}
+
+// inFlightKey identifies an in-flight gauge series.
+type inFlightKey struct {
+	method string
+	route  string
+}
+
+// histogram is a cumulative latency histogram.
+type histogram struct {
+	counts []uint64 // one per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// Metrics collects request counters, latency histograms and in-flight gauges
+// labelled by method, matched route pattern and status, and renders them in
+// the Prometheus text exposition format.
+//
+// Using the route pattern ("/users/:id") rather than the raw path keeps label
+// cardinality bounded. Requests that match no route never reach the
+// middleware chain and are not measured.
+type Metrics struct {
+	namespace string
+	buckets   []float64
+	skipPaths map[string]struct{}
+
+	mu        sync.Mutex
+	requests  map[requestKey]uint64
+	durations map[requestKey]*histogram
+	inFlight  map[inFlightKey]int64
+}
+
+// NewMetrics creates a metrics collector.
+func NewMetrics(config MetricsConfig) *Metrics {
+	namespace := config.Namespace
+	if namespace == "" {
+		namespace = "vayu"
+	}
+	buckets := config.Buckets
+	if len(buckets) == 0 {
+		buckets = DefaultMetricsBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	m := &Metrics{
+		namespace: namespace,
+		buckets:   buckets,
+		skipPaths: make(map[string]struct{}, len(config.SkipPaths)),
+		requests:  make(map[requestKey]uint64),
+		durations: make(map[requestKey]*histogram),
+		inFlight:  make(map[inFlightKey]int64),
+	}
+	for _, p := range config.SkipPaths {
+		m.skipPaths[p] = struct{}{}
+	}
+	return m
+}
+
+// Metrics installs request metrics middleware on the app and serves the
+// collected metrics at path, e.g. app.Metrics("/metrics", vayu.MetricsConfig{}).
+// The metrics endpoint itself is not measured.
+func (a *App) Metrics(path string, config MetricsConfig) *Metrics {
+	config.SkipPaths = append(config.SkipPaths, path)
+	m := NewMetrics(config)
+	a.Use(m.Middleware())
+	a.GET(path, m.Handler())
+	return m
+}
+
+// Middleware returns middleware that measures every request.
+func (m *Metrics) Middleware() HandlerFunc {
+	return func(c *Context, next NextFunc) {
+		if _, ok := m.skipPaths[c.Request.URL.Path]; ok {
+			next()
+			return
+		}
+
+		flight := inFlightKey{method: c.Request.Method, route: c.RoutePattern()}
+		m.mu.Lock()
+		m.inFlight[flight]++
+		m.mu.Unlock()
+
+		start := time.Now()
+		defer func() {
+			status := c.Writer.Status()
+			if status == 0 {
+				status = StatusOK
+			}
+			m.observe(flight, status, time.Since(start).Seconds())
+		}()
+
+		next()
+	}
+}
+
+// observe records a completed request.
+func (m *Metrics) observe(flight inFlightKey, status int, seconds float64) {
+	key := requestKey{method: flight.method, route: flight.route, status: status}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.inFlight[flight]--
+	m.requests[key]++
+
+	h, ok := m.durations[key]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(m.buckets))}
+		m.durations[key] = h
+	}
+	for i, upper := range m.buckets {
+		if seconds <= upper {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.count++
+}
+
+// Handler returns a handler that serves the metrics in the Prometheus text
+// exposition format.
+func (m *Metrics) Handler() HandlerFunc {
+	return func(c *Context, next NextFunc) {
+		c.Writer.Header().Set("Content-Type", MetricsContentType)
+		c.Writer.WriteHeader(StatusOK)
+		if _, err := m.WriteTo(c.Writer); err != nil {
+			c.Logger().Error("error writing metrics", "error", err)
+		}
+	}
+}
+
+// WriteTo writes all metrics to w in the Prometheus text exposition format.
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+
+	m.mu.Lock()
+	m.writeRequests(bw)
+	m.writeDurations(bw)
+	m.writeInFlight(bw)
+	m.mu.Unlock()
+
+	err := bw.Flush()
+	return cw.n, err
+}
+
+func (m *Metrics) writeRequests(w *bufio.Writer) {
+	name := m.namespace + "_http_requests_total"
+	fmt.Fprintf(w, "# HELP %s Total number of HTTP requests.\n", name)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	for _, key := range sortedRequestKeys(m.requests) {
+		fmt.Fprintf(w, "%s{%s} %d\n", name, key.labels(), m.requests[key])
+	}
+}
+
+func (m *Metrics) writeDurations(w *bufio.Writer) {
+	name := m.namespace + "_http_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s HTTP request latency in seconds.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+
+	keys := make([]requestKey, 0, len(m.durations))
+	for key := range m.durations {
+		keys = append(keys, key)
+	}
+	sortRequestKeys(keys)
+
+	for _, key := range keys {
+		h := m.durations[key]
+		labels := key.labels()
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, labels, formatFloat(upper), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, labels, formatFloat(h.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, labels, h.count)
+	}
+}
+
+func (m *Metrics) writeInFlight(w *bufio.Writer) {
+	name := m.namespace + "_http_requests_in_flight"
+	fmt.Fprintf(w, "# HELP %s Number of HTTP requests currently being served.\n", name)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+
+	keys := make([]inFlightKey, 0, len(m.inFlight))
+	for key := range m.inFlight {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{method=\"%s\",route=\"%s\"} %d\n",
+			name, escapeLabelValue(key.method), escapeLabelValue(key.route), m.inFlight[key])
+	}
+}
+
+// labels renders the key as a Prometheus label set without braces.
+func (k requestKey) labels() string {
+	return fmt.Sprintf("method=\"%s\",route=\"%s\",status=\"%d\"",
+		escapeLabelValue(k.method), escapeLabelValue(k.route), k.status)
+}
+
+func sortedRequestKeys(m map[requestKey]uint64) []requestKey {
+	keys := make([]requestKey, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sortRequestKeys(keys)
+	return keys
+}
+
+// sortRequestKeys orders series by route, method and status so output is stable.
+func sortRequestKeys(keys []requestKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+}
+
+// labelValueEscaper escapes label values as required by the exposition format.
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(v string) string {
+	return labelValueEscaper.Replace(v)
+}
+
+// formatFloat renders a sample value the way Prometheus clients do.
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -1,17 +1,50 @@
 package vayu
 
+// chainFrame records where a WithMiddleware sub-chain resumes the chain it
+// interrupted.
+type chainFrame struct {
+	ctx      *Context
+	handlers []HandlerFunc
+	index    int
+	next     NextFunc
+}
+
+// WithMiddleware wraps a handler with route-specific middleware.
+// The middleware run as a sub-chain on the Context, so middleware that hand
+// the chain to another goroutine (such as Timeout) work per route too.
 func WithMiddleware(handler HandlerFunc, middlewares ...HandlerFunc) HandlerFunc {
+	// Compose once; appending to the variadic slice could alias the caller's array
+	sub := buildChain(buildChain(middlewares, handler), resumeChain)
+
 	return func(c *Context, next NextFunc) {
-		full := append(middlewares, handler)
+		depth := len(c.chains)
+		c.chains = append(c.chains, chainFrame{c, c.handlers, c.index, next})
+		c.handlers, c.index = sub, -1
+		c.next()
 
-		var exec func(int)
-		exec = func(i int) {
-			if i < len(full) {
-				full[i](c, func() { exec(i + 1) })
-			} else {
-				next()
-			}
+		if len(c.chains) > depth {
+			// The handler did not call next; leave the outer chain where it was
+			frame := c.chains[depth]
+			clear(c.chains[depth:])
+			c.chains = c.chains[:depth]
+			c.handlers, c.index = frame.handlers, frame.index
 		}
-		exec(0)
 	}
 }
+
+// resumeChain ends every WithMiddleware sub-chain. Calling next from the
+// handler continues the outer chain on whichever context runs it, the
+// original one or a fork of it.
+func resumeChain(c *Context, _ NextFunc) {
+	last := len(c.chains) - 1
+	frame := c.chains[last]
+	c.chains[last] = chainFrame{}
+	c.chains = c.chains[:last]
+
+	c.handlers, c.index = frame.handlers, frame.index
+	if c == frame.ctx {
+		frame.next()
+		return
+	}
+	c.next()
+}
@@ -0,0 +1,413 @@
+package vayu
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MarshalMsgPack returns the MessagePack encoding of v.
+//
+// Structs are encoded as maps keyed by their `msgpack` tags, falling back
+// to `json` tags and then field names; the "omitempty" option and "-" work
+// as in encoding/json, and embedded structs are flattened. time.Time uses
+// the timestamp extension type, other encoding.TextMarshalers are encoded
+// as strings, and []byte as binary. Map keys are sorted when they are
+// strings or numbers. Cyclic values return an error instead of recursing
+// forever.
+func MarshalMsgPack(v any) ([]byte, error) {
+	var e msgPackEncoder
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return e.buf.Bytes(), nil
+}
+
+// msgPackCycleDepth is the number of nested pointers, maps and slices
+// after which the encoder starts checking for cycles, as encoding/json does.
+const msgPackCycleDepth = 1000
+
+// msgPackEncoder holds the output and the references being encoded.
+type msgPackEncoder struct {
+	buf   bytes.Buffer
+	depth int
+	// path holds the references on the way to the current value once depth
+	// passes msgPackCycleDepth; meeting one again means the value is cyclic
+	path map[msgPackRef]bool
+}
+
+// msgPackRef identifies a pointer, map or slice. Slices sharing an array
+// differ in length, and a struct shares its address with its first field.
+type msgPackRef struct {
+	ptr uintptr
+	len int
+	typ reflect.Type
+}
+
+// enter records val on the current path, returning an error if it is
+// already there.
+func (e *msgPackEncoder) enter(val reflect.Value) (msgPackRef, error) {
+	ref := msgPackRef{ptr: val.Pointer(), typ: val.Type()}
+	if val.Kind() == reflect.Slice {
+		ref.len = val.Len()
+	}
+	if e.path[ref] {
+		return ref, fmt.Errorf("msgpack: encountered a cycle via %s", val.Type())
+	}
+	if e.path == nil {
+		e.path = make(map[msgPackRef]bool)
+	}
+	e.path[ref] = true
+	return ref, nil
+}
+
+// encode writes the encoding of val.
+func (e *msgPackEncoder) encode(val reflect.Value) error {
+	buf := &e.buf
+	if !val.IsValid() {
+		buf.WriteByte(0xc0)
+		return nil
+	}
+
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if val.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+	}
+
+	if val.Kind() != reflect.Pointer && val.Kind() != reflect.Interface && val.CanInterface() {
+		if t, ok := val.Interface().(time.Time); ok {
+			writeMsgPackTime(buf, t)
+			return nil
+		}
+		if m, ok := textMarshaler(val); ok {
+			text, err := m.MarshalText()
+			if err != nil {
+				return err
+			}
+			writeMsgPackString(buf, string(text))
+			return nil
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if val.IsNil() {
+			break
+		}
+		e.depth++
+		defer func() { e.depth-- }()
+		if e.depth > msgPackCycleDepth {
+			ref, err := e.enter(val)
+			if err != nil {
+				return err
+			}
+			defer delete(e.path, ref)
+		}
+	}
+
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		return e.encode(val.Elem())
+
+	case reflect.Bool:
+		if val.Bool() {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeMsgPackInt(buf, val.Int())
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeMsgPackUint(buf, val.Uint())
+
+	case reflect.Float32:
+		buf.WriteByte(0xca)
+		buf.Write(binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(val.Float()))))
+
+	case reflect.Float64:
+		buf.WriteByte(0xcb)
+		buf.Write(binary.BigEndian.AppendUint64(nil, math.Float64bits(val.Float())))
+
+	case reflect.String:
+		writeMsgPackString(buf, val.String())
+
+	case reflect.Slice, reflect.Array:
+		if val.Kind() == reflect.Slice && val.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			writeMsgPackBinary(buf, bytesOf(val))
+			return nil
+		}
+		writeMsgPackHeader(buf, val.Len(), 0x90, 0xdc, 0xdd, 16)
+		for i := 0; i < val.Len(); i++ {
+			if err := e.encode(val.Index(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if val.IsNil() {
+			buf.WriteByte(0xc0)
+			return nil
+		}
+		keys := val.MapKeys()
+		sortMapKeys(keys)
+		writeMsgPackHeader(buf, len(keys), 0x80, 0xde, 0xdf, 16)
+		for _, key := range keys {
+			if err := e.encode(key); err != nil {
+				return err
+			}
+			if err := e.encode(val.MapIndex(key)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Struct:
+		fields := msgPackFields(val)
+		writeMsgPackHeader(buf, len(fields), 0x80, 0xde, 0xdf, 16)
+		for _, field := range fields {
+			writeMsgPackString(buf, field.name)
+			if err := e.encode(field.value); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("msgpack: unsupported type %s", val.Type())
+	}
+	return nil
+}
+
+// textMarshaler returns val as an encoding.TextMarshaler, using its address
+// for methods with pointer receivers.
+func textMarshaler(val reflect.Value) (encoding.TextMarshaler, bool) {
+	if m, ok := val.Interface().(encoding.TextMarshaler); ok {
+		return m, true
+	}
+	if val.CanAddr() {
+		m, ok := val.Addr().Interface().(encoding.TextMarshaler)
+		return m, ok
+	}
+	return nil, false
+}
+
+// msgPackField is a struct field to encode.
+type msgPackField struct {
+	name  string
+	value reflect.Value
+}
+
+// msgPackFields returns the fields of a struct to encode, flattening
+// embedded structs other than time.Time and encoding.TextMarshalers, which
+// are encoded whole under their type name when exported.
+func msgPackFields(val reflect.Value) []msgPackField {
+	var fields []msgPackField
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		tag, ok := field.Tag.Lookup("msgpack")
+		if !ok {
+			tag = field.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fieldValue := val.Field(i)
+
+		if field.Anonymous && name == "" && !isMsgPackScalar(field.Type) {
+			embedded := fieldValue
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, msgPackFields(embedded)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(","+opts+",", ",omitempty,") && isEmptyMsgPackValue(fieldValue) {
+			continue
+		}
+		fields = append(fields, msgPackField{name, fieldValue})
+	}
+	return fields
+}
+
+// isMsgPackScalar reports whether values of t are encoded as a single value
+// rather than a map of their fields.
+func isMsgPackScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t == timeType || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// isEmptyMsgPackValue reports whether omitempty drops val, as in encoding/json.
+func isEmptyMsgPackValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return val.Len() == 0
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.Interface, reflect.Pointer:
+		return val.IsZero()
+	}
+	return false
+}
+
+// sortMapKeys sorts string and numeric keys so the encoding is deterministic.
+func sortMapKeys(keys []reflect.Value) {
+	if len(keys) == 0 {
+		return
+	}
+	switch keys[0].Kind() {
+	case reflect.String:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Int() < keys[j].Int() })
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		sort.Slice(keys, func(i, j int) bool { return keys[i].Uint() < keys[j].Uint() })
+	}
+}
+
+// bytesOf returns the contents of a byte slice or array.
+func bytesOf(val reflect.Value) []byte {
+	if val.Kind() == reflect.Slice {
+		return val.Bytes()
+	}
+	b := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(b), val)
+	return b
+}
+
+// writeMsgPackInt writes n in the smallest integer format.
+func writeMsgPackInt(buf *bytes.Buffer, n int64) {
+	switch {
+	case n >= 0:
+		writeMsgPackUint(buf, uint64(n))
+	case n >= -32:
+		buf.WriteByte(byte(n))
+	case n >= math.MinInt8:
+		buf.Write([]byte{0xd0, byte(n)})
+	case n >= math.MinInt16:
+		buf.WriteByte(0xd1)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n >= math.MinInt32:
+		buf.WriteByte(0xd2)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(0xd3)
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(n)))
+	}
+}
+
+// writeMsgPackUint writes n in the smallest integer format.
+func writeMsgPackUint(buf *bytes.Buffer, n uint64) {
+	switch {
+	case n <= 0x7f:
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xcc, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xcd)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	case n <= math.MaxUint32:
+		buf.WriteByte(0xce)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	default:
+		buf.WriteByte(0xcf)
+		buf.Write(binary.BigEndian.AppendUint64(nil, n))
+	}
+}
+
+// writeMsgPackString writes a str value.
+func writeMsgPackString(buf *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xd9, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(0xdb)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	buf.WriteString(s)
+}
+
+// writeMsgPackBinary writes a bin value.
+func writeMsgPackBinary(buf *bytes.Buffer, b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf.Write([]byte{0xc4, byte(n)})
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xc5)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(0xc6)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+	buf.Write(b)
+}
+
+// writeMsgPackHeader writes the header of an array or map with n entries,
+// using the fix format for fewer than fixMax entries.
+func writeMsgPackHeader(buf *bytes.Buffer, n int, fix, code16, code32 byte, fixMax int) {
+	switch {
+	case n < fixMax:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(code16)
+		buf.Write(binary.BigEndian.AppendUint16(nil, uint16(n)))
+	default:
+		buf.WriteByte(code32)
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(n)))
+	}
+}
+
+// writeMsgPackTime writes t with the timestamp extension type (-1) in the
+// smallest of its 32, 64 and 96-bit formats.
+func writeMsgPackTime(buf *bytes.Buffer, t time.Time) {
+	sec, nsec := t.Unix(), int64(t.Nanosecond())
+	switch {
+	case sec>>34 == 0 && nsec == 0 && sec <= math.MaxUint32:
+		buf.Write([]byte{0xd6, 0xff})
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(sec)))
+	case sec>>34 == 0:
+		buf.Write([]byte{0xd7, 0xff})
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(nsec)<<34|uint64(sec)))
+	default:
+		buf.Write([]byte{0xc7, 12, 0xff})
+		buf.Write(binary.BigEndian.AppendUint32(nil, uint32(nsec)))
+		buf.Write(binary.BigEndian.AppendUint64(nil, uint64(sec)))
+	}
+}
@@ -0,0 +1,318 @@
+package vayu
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNotAcceptable is wrapped by the error Negotiate returns when no
+	// renderer produces a media type the request's Accept header allows.
+	ErrNotAcceptable = errors.New("not acceptable")
+
+	// ErrNotRenderable is returned by a Renderer, before writing anything,
+	// when it cannot represent the data. Negotiate then tries the next
+	// acceptable media type.
+	ErrNotRenderable = errors.New("data cannot be rendered in this media type")
+)
+
+// Renderer writes data as the response body with the given status code,
+// setting the Content-Type header itself.
+type Renderer func(c *Context, code int, data any) error
+
+// Offer pairs a media type with the renderer that produces it.
+type Offer struct {
+	MediaType string
+	Renderer  Renderer
+}
+
+var (
+	renderersMu sync.RWMutex
+	// renderers are the registered offers in order of server preference
+	renderers = []Offer{
+		{"application/json", renderJSON},
+		{"application/xml", renderXML},
+		{"text/plain", renderText},
+		{"text/html", renderHTML},
+		{"application/yaml", (*Context).YAML},
+		{"text/csv", renderCSV},
+		{"application/msgpack", (*Context).MsgPack},
+	}
+)
+
+// RegisterRenderer registers the renderer Negotiate uses for a media type,
+// such as "application/msgpack". A new media type is offered after the
+// existing ones; an existing one keeps its position and is replaced.
+// A nil renderer removes the registration.
+func RegisterRenderer(mediaType string, renderer Renderer) {
+	mediaType = strings.ToLower(mediaType)
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	for i, offer := range renderers {
+		if offer.MediaType != mediaType {
+			continue
+		}
+		if renderer == nil {
+			renderers = append(renderers[:i:i], renderers[i+1:]...)
+		} else {
+			renderers[i].Renderer = renderer
+		}
+		return
+	}
+	if renderer != nil {
+		renderers = append(renderers, Offer{mediaType, renderer})
+	}
+}
+
+// Negotiate renders data in the media type the client prefers according to
+// the Accept header and its q-values, and sets "Vary: Accept". Offers take
+// precedence over the registered renderers, in the given order; among
+// equally acceptable types the earlier offer wins. Requests without an
+// Accept header get the first offer, JSON by default.
+//
+//	c.Negotiate(vayu.StatusOK, user, vayu.Offer{
+//		MediaType: "text/html",
+//		Renderer:  vayu.TemplateRenderer(tmpl, "user.html"),
+//	})
+//
+// When nothing acceptable can be rendered it writes nothing and returns an
+// *HTTPError with status 406 wrapping ErrNotAcceptable.
+func (c *Context) Negotiate(code int, data any, offers ...Offer) error {
+	addVary(c.Writer.Header(), "Accept")
+
+	candidates := negotiationOffers(offers)
+	accept := parseAccept(c.Request.Header.Values("Accept"))
+
+	type match struct {
+		offer Offer
+		q     float64
+	}
+	var matches []match
+	for _, offer := range candidates {
+		if q := accept.quality(offer.MediaType); q > 0 {
+			matches = append(matches, match{offer, q})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].q > matches[j].q
+	})
+
+	for _, m := range matches {
+		if err := m.offer.Renderer(c, code, data); err != ErrNotRenderable {
+			return err
+		}
+	}
+
+	types := make([]string, len(candidates))
+	for i, offer := range candidates {
+		types[i] = offer.MediaType
+	}
+	return &HTTPError{
+		Code:    StatusNotAcceptable,
+		Message: "not acceptable: available media types are " + strings.Join(types, ", "),
+		Err:     ErrNotAcceptable,
+	}
+}
+
+// negotiationOffers returns offers followed by the registered renderers
+// whose media types offers do not cover.
+func negotiationOffers(offers []Offer) []Offer {
+	candidates := make([]Offer, 0, len(offers))
+	seen := make(map[string]bool)
+	for _, offer := range offers {
+		mediaType := strings.ToLower(offer.MediaType)
+		if offer.Renderer == nil || seen[mediaType] {
+			continue
+		}
+		seen[mediaType] = true
+		candidates = append(candidates, Offer{mediaType, offer.Renderer})
+	}
+
+	renderersMu.RLock()
+	defer renderersMu.RUnlock()
+	for _, offer := range renderers {
+		if !seen[offer.MediaType] {
+			candidates = append(candidates, offer)
+		}
+	}
+	return candidates
+}
+
+// mediaRange is one entry of an Accept header.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+type acceptHeader []mediaRange
+
+// parseAccept parses Accept header values. A missing or empty header
+// accepts anything.
+func parseAccept(values []string) acceptHeader {
+	var ranges acceptHeader
+	for _, value := range values {
+		for _, entry := range splitList(value) {
+			params := strings.Split(entry, ";")
+			typ, subtype, ok := strings.Cut(strings.ToLower(strings.TrimSpace(params[0])), "/")
+			if !ok || typ == "" || subtype == "" || (typ == "*" && subtype != "*") {
+				continue
+			}
+
+			r := mediaRange{typ: typ, subtype: subtype, q: 1}
+			for _, param := range params[1:] {
+				key, val, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "q") {
+					continue
+				}
+				q, err := strconv.ParseFloat(val, 64)
+				if err != nil || q < 0 || q > 1 {
+					q = 0
+				}
+				r.q = q
+			}
+			ranges = append(ranges, r)
+		}
+	}
+	if len(ranges) == 0 {
+		ranges = acceptHeader{{typ: "*", subtype: "*", q: 1}}
+	}
+	return ranges
+}
+
+// quality returns the q-value the most specific matching range assigns
+// to mediaType, or 0 if none matches.
+func (a acceptHeader) quality(mediaType string) float64 {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	q, specificity := 0.0, -1
+	for _, r := range a {
+		var s int
+		switch {
+		case r.typ == typ && r.subtype == subtype:
+			s = 2
+		case r.typ == typ && r.subtype == "*":
+			s = 1
+		case r.typ == "*":
+			s = 0
+		default:
+			continue
+		}
+		if s > specificity {
+			q, specificity = r.q, s
+		}
+	}
+	return q
+}
+
+// addVary adds a field to the Vary header unless it is already listed.
+func addVary(h http.Header, field string) {
+	for _, value := range h.Values("Vary") {
+		for _, existing := range splitList(value) {
+			if existing == "*" || strings.EqualFold(existing, field) {
+				return
+			}
+		}
+	}
+	h.Add("Vary", field)
+}
+
+// TemplateRenderer returns a Renderer that executes the named template of t
+// with the data as text/html. Nothing is written if execution fails.
+func TemplateRenderer(t *template.Template, name string) Renderer {
+	return func(c *Context, code int, data any) error {
+		var buf bytes.Buffer
+		if err := t.ExecuteTemplate(&buf, name, data); err != nil {
+			return err
+		}
+		_, err := c.HTML(code, buf.String())
+		return err
+	}
+}
+
+// renderJSON is the Renderer for application/json.
+func renderJSON(c *Context, code int, data any) error {
+	return c.JSON(code, data)
+}
+
+// renderXML is the Renderer for application/xml. Values encoding/xml cannot
+// represent, such as maps, are not renderable.
+func renderXML(c *Context, code int, data any) error {
+	err := c.XML(code, data)
+	var unsupported *xml.UnsupportedTypeError
+	if errors.As(err, &unsupported) {
+		return ErrNotRenderable
+	}
+	return err
+}
+
+// renderCSV is the Renderer for text/csv. Only [][]string and slices of
+// structs are renderable.
+func renderCSV(c *Context, code int, data any) error {
+	if _, ok := data.([][]string); !ok {
+		val := reflect.ValueOf(data)
+		if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+			return ErrNotRenderable
+		}
+		if _, err := csvColumns(val.Type().Elem()); err != nil {
+			return ErrNotRenderable
+		}
+	}
+	return c.CSV(code, data)
+}
+
+// renderText is the Renderer for text/plain. It renders strings, byte
+// slices, fmt.Stringers, errors, booleans and numbers.
+func renderText(c *Context, code int, data any) error {
+	text, ok := plainText(data)
+	if !ok {
+		return ErrNotRenderable
+	}
+	_, err := c.Send(code, text)
+	return err
+}
+
+// plainText formats data as text if it has a natural text form.
+func plainText(data any) (string, bool) {
+	switch v := data.(type) {
+	case string:
+		return v, true
+	case []byte:
+		return string(v), true
+	case fmt.Stringer:
+		return v.String(), true
+	case error:
+		return v.Error(), true
+	}
+	switch reflect.ValueOf(data).Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(data), true
+	}
+	return "", false
+}
+
+// renderHTML is the Renderer for text/html. It renders template.HTML as is
+// and escapes plain strings; other data needs a TemplateRenderer offer.
+func renderHTML(c *Context, code int, data any) error {
+	var html string
+	switch v := data.(type) {
+	case template.HTML:
+		html = string(v)
+	case string:
+		html = template.HTMLEscapeString(v)
+	default:
+		return ErrNotRenderable
+	}
+	_, err := c.HTML(code, html)
+	return err
+}
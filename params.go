@@ -0,0 +1,105 @@
+package vayu
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrParamMissing is wrapped by ParamError when the matched route has no
+// such parameter or its value is empty.
+var ErrParamMissing = errors.New("parameter is missing")
+
+// ParamError describes a path parameter that is missing or cannot be parsed.
+// Through the default error handler it produces a 400 Bad Request.
+type ParamError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e *ParamError) Error() string {
+	if errors.Is(e.Err, ErrParamMissing) {
+		return fmt.Sprintf("path parameter %s is missing", e.Name)
+	}
+	return fmt.Sprintf("path parameter %s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying parse error.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// StatusCode implements StatusCoder.
+func (e *ParamError) StatusCode() int {
+	return StatusBadRequest
+}
+
+// PathParamType lists the types PathParam can parse a path parameter into.
+type PathParamType interface {
+	~string | ~bool |
+		~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64 |
+		UUID
+}
+
+// Param returns the value of the path parameter with the given name,
+// or an empty string if the route has no such parameter.
+func (c *Context) Param(name string) string {
+	return c.Params[name]
+}
+
+// ParamInt returns the path parameter with the given name as an int.
+func (c *Context) ParamInt(name string) (int, error) {
+	return PathParam[int](c, name)
+}
+
+// ParamInt64 returns the path parameter with the given name as an int64.
+func (c *Context) ParamInt64(name string) (int64, error) {
+	return PathParam[int64](c, name)
+}
+
+// ParamUUID returns the path parameter with the given name as a UUID.
+func (c *Context) ParamUUID(name string) (UUID, error) {
+	return PathParam[UUID](c, name)
+}
+
+// PathParam parses a path parameter into a specific type.
+// This provides compile-time type safety through generics.
+// A missing or malformed parameter returns a *ParamError.
+// Usage: id, err := vayu.PathParam[int64](c, "id")
+func PathParam[T PathParamType](c *Context, name string) (T, error) {
+	var result T
+	value, ok := c.Params[name]
+	if !ok || value == "" {
+		return result, &ParamError{Name: name, Err: ErrParamMissing}
+	}
+
+	if u, ok := any(&result).(*UUID); ok {
+		parsed, err := ParseUUID(value)
+		if err != nil {
+			return result, &ParamError{Name: name, Value: value, Err: fmt.Errorf("cannot convert '%s' to UUID: %w", value, err)}
+		}
+		*u = parsed
+		return result, nil
+	}
+
+	if err := setFieldFromString(reflect.ValueOf(&result).Elem(), value, ""); err != nil {
+		return result, &ParamError{Name: name, Value: value, Err: err}
+	}
+	return result, nil
+}
+
+// MustPathParam parses a path parameter into a specific type and panics
+// with a *ParamError if it is missing or malformed. Combined with
+// ErrorHandlerMiddleware this results in a 400 Bad Request.
+// Usage: id := vayu.MustPathParam[int](c, "id")
+func MustPathParam[T PathParamType](c *Context, name string) T {
+	result, err := PathParam[T](c, name)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
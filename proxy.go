@@ -0,0 +1,253 @@
+package vayu
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// Forwarding header names.
+const (
+	HeaderForwarded       = "Forwarded"
+	HeaderXForwardedFor   = "X-Forwarded-For"
+	HeaderXForwardedProto = "X-Forwarded-Proto"
+	HeaderXForwardedHost  = "X-Forwarded-Host"
+	HeaderXRealIP         = "X-Real-IP"
+)
+
+// SetTrustedProxies sets the proxies whose forwarding headers ClientIP,
+// Scheme and Host honor. Each entry is a CIDR such as "10.0.0.0/8" or a
+// single IP address. Without trusted proxies the headers are ignored,
+// since any client can send them.
+func (a *App) SetTrustedProxies(proxies ...string) error {
+	prefixes := make([]netip.Prefix, 0, len(proxies))
+	for _, proxy := range proxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			return err
+		}
+		prefixes = append(prefixes, prefix)
+	}
+	a.trustedProxies = prefixes
+	return nil
+}
+
+// parseProxy parses a CIDR or a single IP address.
+func parseProxy(proxy string) (netip.Prefix, error) {
+	proxy = strings.TrimSpace(proxy)
+	if strings.Contains(proxy, "/") {
+		prefix, err := netip.ParsePrefix(proxy)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(proxy)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid trusted proxy %q: %w", proxy, err)
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// isTrustedProxy reports whether ip belongs to a trusted proxy.
+func (a *App) isTrustedProxy(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range a.trustedProxies {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the IP address of the client. When the immediate peer
+// is a trusted proxy, the Forwarded, X-Forwarded-For and X-Real-IP headers
+// are followed back through trusted proxies to the first untrusted address.
+func (c *Context) ClientIP() string {
+	hop, _ := c.clientHop()
+	return hop.ip
+}
+
+// Scheme returns "https" or "http" as seen by the client, honoring the
+// Forwarded and X-Forwarded-Proto headers from trusted proxies.
+func (c *Context) Scheme() string {
+	if hop, trusted := c.clientHop(); trusted {
+		if proto := strings.ToLower(hop.proto); proto == "http" || proto == "https" {
+			return proto
+		}
+	}
+	if c.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// Host returns the host requested by the client, honoring the Forwarded
+// and X-Forwarded-Host headers from trusted proxies.
+func (c *Context) Host() string {
+	if hop, trusted := c.clientHop(); trusted && hop.host != "" {
+		return hop.host
+	}
+	return c.Request.Host
+}
+
+// forwardedHop is one connection recorded by a proxy.
+type forwardedHop struct {
+	ip    string
+	proto string
+	host  string
+}
+
+// clientHop resolves the connection made by the client. It reports whether
+// the result came from trusted forwarding headers.
+func (c *Context) clientHop() (forwardedHop, bool) {
+	r := c.Request
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(peer); err == nil {
+		peer = host
+	}
+
+	if c.app == nil || !c.app.isTrustedProxy(peer) {
+		return forwardedHop{ip: peer}, false
+	}
+
+	chain := forwardedChain(r.Header)
+	if len(chain) == 0 {
+		hop := forwardedHop{ip: peer, proto: lastValue(r.Header, HeaderXForwardedProto), host: lastValue(r.Header, HeaderXForwardedHost)}
+		if realIP := strings.TrimSpace(r.Header.Get(HeaderXRealIP)); isIP(realIP) {
+			hop.ip = realIP
+		}
+		return hop, true
+	}
+
+	// Walk back from the nearest proxy until an untrusted address
+	client := forwardedHop{ip: peer}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !isIP(chain[i].ip) {
+			break
+		}
+		client = chain[i]
+		if !c.app.isTrustedProxy(client.ip) {
+			break
+		}
+	}
+	return client, true
+}
+
+// forwardedChain returns the hops recorded in the Forwarded header, or in
+// X-Forwarded-For if there is none, oldest first.
+func forwardedChain(h http.Header) []forwardedHop {
+	if values := h.Values(HeaderForwarded); len(values) > 0 {
+		return parseForwarded(values)
+	}
+
+	var ips []string
+	for _, value := range h.Values(HeaderXForwardedFor) {
+		ips = append(ips, splitList(value)...)
+	}
+	if len(ips) == 0 {
+		return nil
+	}
+
+	protos := headerList(h, HeaderXForwardedProto)
+	hosts := headerList(h, HeaderXForwardedHost)
+	chain := make([]forwardedHop, len(ips))
+	for i, ip := range ips {
+		chain[i].ip = ip
+		chain[i].proto = listValue(protos, i, len(ips))
+		chain[i].host = listValue(hosts, i, len(ips))
+	}
+	return chain
+}
+
+// listValue returns the entry of a X-Forwarded-Proto or -Host list for hop
+// i of n. Lists with one entry per hop are matched by position; otherwise
+// the last entry, added by the nearest proxy, applies to every hop.
+func listValue(list []string, i, n int) string {
+	if len(list) == 0 {
+		return ""
+	}
+	if len(list) == n {
+		return list[i]
+	}
+	return list[len(list)-1]
+}
+
+// parseForwarded parses RFC 7239 Forwarded header values.
+func parseForwarded(values []string) []forwardedHop {
+	var chain []forwardedHop
+	for _, value := range values {
+		for _, element := range splitList(value) {
+			var hop forwardedHop
+			for _, pair := range strings.Split(element, ";") {
+				key, val, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					continue
+				}
+				val = strings.Trim(val, `"`)
+				switch strings.ToLower(key) {
+				case "for":
+					hop.ip = forwardedNode(val)
+				case "proto":
+					hop.proto = val
+				case "host":
+					hop.host = val
+				}
+			}
+			chain = append(chain, hop)
+		}
+	}
+	return chain
+}
+
+// forwardedNode extracts the IP from a Forwarded node such as
+// "192.0.2.60:8080" or "[2001:db8::1]:4711". Obfuscated and "unknown"
+// nodes are returned unchanged and treated as invalid addresses.
+func forwardedNode(node string) string {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(node, "["), "]")
+}
+
+// headerList returns the comma-separated entries of every value of a header.
+func headerList(h http.Header, name string) []string {
+	var list []string
+	for _, value := range h.Values(name) {
+		list = append(list, splitList(value)...)
+	}
+	return list
+}
+
+// lastValue returns the last entry of a comma-separated header.
+func lastValue(h http.Header, name string) string {
+	list := headerList(h, name)
+	if len(list) == 0 {
+		return ""
+	}
+	return list[len(list)-1]
+}
+
+// splitList splits a comma-separated header value, dropping empty entries.
+func splitList(value string) []string {
+	var list []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// isIP reports whether s is an IP address.
+func isIP(s string) bool {
+	_, err := netip.ParseAddr(s)
+	return err == nil
+}
@@ -0,0 +1,219 @@
+package vayu
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// ErrUnsafeRedirect is wrapped by the error Redirect returns when safe
+// redirects are enabled and the target is not allowed.
+var ErrUnsafeRedirect = errors.New("unsafe redirect target")
+
+// SafeRedirectConfig restricts the targets Redirect and Back accept; see
+// App.SetSafeRedirects.
+type SafeRedirectConfig struct {
+	// AllowedHosts lists the hosts absolute targets may point to, such as
+	// "example.com" or "*.example.com" for its subdomains. The request's own
+	// host is always allowed.
+	AllowedHosts []string
+
+	// AllowedPaths restricts relative targets to these path prefixes, such
+	// as "/account". Empty allows any relative path.
+	AllowedPaths []string
+}
+
+// SetSafeRedirects makes Redirect reject targets that config does not
+// allow, preventing open redirects through user-supplied URLs.
+func (a *App) SetSafeRedirects(config SafeRedirectConfig) *App {
+	a.safeRedirects = &config
+	return a
+}
+
+// Name names the most recently registered route so URL and
+// RedirectToRoute can build paths to it:
+//
+//	app.GET("/users/:id", showUser).Name("users.show")
+//
+// It panics if no route was registered or the name is taken.
+func (a *App) Name(name string) *App {
+	if a.lastRoute == "" {
+		panic("vayu: Name called before registering a route")
+	}
+	if _, exists := a.router.names[name]; exists {
+		panic(fmt.Sprintf("vayu: route name %q already registered", name))
+	}
+	a.router.names[name] = a.lastRoute
+	return a
+}
+
+// URL builds the path of the named route, filling its parameters from
+// params. Parameters the pattern does not use are added as a query string.
+// It returns an error for unknown names and missing parameters.
+func (a *App) URL(name string, params map[string]string) (string, error) {
+	pattern, ok := a.router.names[name]
+	if !ok {
+		return "", fmt.Errorf("no route named %q", name)
+	}
+
+	used := make(map[string]bool)
+	parts := splitPath(pattern)
+	for i, part := range parts {
+		if !strings.HasPrefix(part, ":") {
+			continue
+		}
+		value, ok := params[part[1:]]
+		if !ok || value == "" {
+			return "", fmt.Errorf("route %q: missing parameter %q", name, part[1:])
+		}
+		parts[i] = url.PathEscape(value)
+		used[part[1:]] = true
+	}
+
+	target := "/" + strings.Join(parts, "/")
+	query := url.Values{}
+	for key, value := range params {
+		if !used[key] {
+			query.Set(key, value)
+		}
+	}
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	return target, nil
+}
+
+// Redirect redirects the client to target with a 3xx status code, such as
+// StatusFound or StatusSeeOther. Relative targets are resolved against the
+// request path. When safe redirects are enabled, a target they do not allow
+// produces an *HTTPError with status 400 wrapping ErrUnsafeRedirect and
+// nothing is written.
+func (c *Context) Redirect(code int, target string) error {
+	if code < StatusMultipleChoices || code > StatusPermanentRedirect {
+		return fmt.Errorf("invalid redirect status code %d", code)
+	}
+	if c.app != nil && c.app.safeRedirects != nil && !c.redirectAllowed(target, *c.app.safeRedirects) {
+		return &HTTPError{
+			Code:    StatusBadRequest,
+			Message: "redirect target not allowed",
+			Err:     ErrUnsafeRedirect,
+		}
+	}
+	http.Redirect(c.Writer, c.Request, target, code)
+	return nil
+}
+
+// RedirectToRoute redirects the client to the named route with a 302
+// Found; see App.URL.
+func (c *Context) RedirectToRoute(name string, params map[string]string) error {
+	if c.app == nil {
+		return fmt.Errorf("no route named %q", name)
+	}
+	target, err := c.app.URL(name, params)
+	if err != nil {
+		return err
+	}
+	http.Redirect(c.Writer, c.Request, target, StatusFound)
+	return nil
+}
+
+// Back redirects the client to the page in the Referer header with a 302
+// Found, or to fallback when there is none. Referers on other hosts are
+// ignored unless safe redirects allow them, since any site can link here.
+func (c *Context) Back(fallback string) error {
+	var config SafeRedirectConfig
+	if c.app != nil && c.app.safeRedirects != nil {
+		config = *c.app.safeRedirects
+	}
+	if referer := c.Request.Referer(); referer != "" && c.redirectAllowed(referer, config) {
+		http.Redirect(c.Writer, c.Request, referer, StatusFound)
+		return nil
+	}
+	return c.Redirect(StatusFound, fallback)
+}
+
+// IsSafeRedirect reports whether target may be redirected to under the
+// app's safe redirect configuration, or, without one, whether it stays on
+// the request's host. Use it to check a "next" parameter before using it.
+func (c *Context) IsSafeRedirect(target string) bool {
+	var config SafeRedirectConfig
+	if c.app != nil && c.app.safeRedirects != nil {
+		config = *c.app.safeRedirects
+	}
+	return c.redirectAllowed(target, config)
+}
+
+// redirectAllowed reports whether config allows redirecting to target.
+func (c *Context) redirectAllowed(target string, config SafeRedirectConfig) bool {
+	// Browsers treat backslashes as slashes, so "/\evil.com" is another host
+	if target == "" || strings.ContainsAny(target, "\\") || strings.IndexFunc(target, isControl) >= 0 {
+		return false
+	}
+	u, err := url.Parse(target)
+	if err != nil {
+		return false
+	}
+
+	if u.Scheme == "" && u.Host == "" {
+		if len(config.AllowedPaths) == 0 {
+			return true
+		}
+		return strings.HasPrefix(u.Path, "/") && allowedPath(path.Clean(u.Path), config.AllowedPaths)
+	}
+
+	if u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return false
+	}
+	if host == requestHostname(c.Host()) {
+		return true
+	}
+	return allowedHost(host, config.AllowedHosts)
+}
+
+// requestHostname returns the lowercased host name of a Host header value,
+// without port or IPv6 brackets.
+func requestHostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		hostport = host
+	}
+	return strings.ToLower(strings.Trim(hostport, "[]"))
+}
+
+// allowedHost reports whether host matches one of the allowed hosts.
+func allowedHost(host string, allowed []string) bool {
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// allowedPath reports whether p is one of the allowed paths or below one.
+func allowedPath(p string, allowed []string) bool {
+	for _, prefix := range allowed {
+		prefix = strings.TrimSuffix(prefix, "/")
+		if prefix == "" || p == prefix || strings.HasPrefix(p, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isControl reports whether r is an ASCII control character.
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
@@ -0,0 +1,239 @@
+package vayu
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// XML sends obj encoded with encoding/xml, preceded by the XML header.
+// Nothing is written if encoding fails.
+func (c *Context) XML(code int, obj any) error {
+	out, err := xml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return c.sendBytes(code, "application/xml", append([]byte(xml.Header), out...))
+}
+
+// YAML sends obj encoded as YAML. Fields are named by their `yaml` tags,
+// or their lowercased names. Nothing is written if encoding fails.
+func (c *Context) YAML(code int, obj any) error {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return c.sendBytes(code, "application/yaml", out)
+}
+
+// MsgPack sends obj encoded as MessagePack; see MarshalMsgPack.
+// Nothing is written if encoding fails.
+func (c *Context) MsgPack(code int, obj any) error {
+	out, err := MarshalMsgPack(obj)
+	if err != nil {
+		return err
+	}
+	return c.sendBytes(code, "application/msgpack", out)
+}
+
+// sendBytes writes body with the given status and Content-Type.
+func (c *Context) sendBytes(code int, contentType string, body []byte) error {
+	c.Writer.Header().Set("Content-Type", contentType)
+	c.Writer.WriteHeader(code)
+	_, err := c.Writer.Write(body)
+	return err
+}
+
+// CSV streams rows as text/csv with a header line. rows is a [][]string
+// written as is, or a slice of structs or struct pointers whose `csv` tags
+// name the columns; untagged fields use the field name and "-" skips a
+// field. Columns can hold strings, booleans, numbers, encoding.TextMarshalers
+// and time.Time, formatted with a `format` tag or as RFC 3339, or pointers
+// to them, with nil written as an empty cell.
+//
+// Unsupported row or column types are reported before anything is written.
+func (c *Context) CSV(code int, rows any) error {
+	if records, ok := rows.([][]string); ok {
+		c.Writer.Header().Set("Content-Type", "text/csv")
+		c.Writer.WriteHeader(code)
+		return csv.NewWriter(c.Writer).WriteAll(records)
+	}
+
+	val := reflect.ValueOf(rows)
+	if val.Kind() == reflect.Pointer && !val.IsNil() {
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Slice && val.Kind() != reflect.Array {
+		return fmt.Errorf("CSV needs a slice of structs, got %T", rows)
+	}
+	columns, err := csvColumns(val.Type().Elem())
+	if err != nil {
+		return err
+	}
+
+	c.Writer.Header().Set("Content-Type", "text/csv")
+	c.Writer.WriteHeader(code)
+	w := csv.NewWriter(c.Writer)
+
+	record := make([]string, len(columns))
+	for i, column := range columns {
+		record[i] = column.name
+	}
+	if err := w.Write(record); err != nil {
+		return err
+	}
+
+	for i := 0; i < val.Len(); i++ {
+		row := val.Index(i)
+		if row.Kind() == reflect.Pointer {
+			if row.IsNil() {
+				continue
+			}
+			row = row.Elem()
+		}
+		for j, column := range columns {
+			cell, err := csvCell(row.FieldByIndex(column.index), column.format)
+			if err != nil {
+				return fmt.Errorf("CSV column %s: %w", column.name, err)
+			}
+			record[j] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvColumn is a struct field written as a CSV column.
+type csvColumn struct {
+	name   string
+	index  []int
+	format string
+}
+
+// csvColumns returns the columns of a row type, flattening embedded structs.
+func csvColumns(rowType reflect.Type) ([]csvColumn, error) {
+	if rowType.Kind() == reflect.Pointer {
+		rowType = rowType.Elem()
+	}
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("CSV needs a slice of structs, got a slice of %s", rowType)
+	}
+
+	var columns []csvColumn
+	for i := 0; i < rowType.NumField(); i++ {
+		field := rowType.Field(i)
+		tag := field.Tag.Get("csv")
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+
+		if field.Anonymous && tag == "" && field.Type.Kind() == reflect.Struct && !isCSVScalar(field.Type) {
+			embedded, err := csvColumns(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for _, column := range embedded {
+				column.index = append([]int{i}, column.index...)
+				columns = append(columns, column)
+			}
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = field.Name
+		}
+		if !isCSVScalar(field.Type) {
+			return nil, fmt.Errorf("CSV column %s: unsupported type %s", name, field.Type)
+		}
+		columns = append(columns, csvColumn{name: name, index: []int{i}, format: field.Tag.Get("format")})
+	}
+	return columns, nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// isCSVScalar reports whether values of t fit in a single CSV cell.
+func isCSVScalar(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// csvCell formats a column value.
+func csvCell(val reflect.Value, format string) (string, error) {
+	if val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return "", nil
+		}
+		val = val.Elem()
+	}
+
+	if t, ok := val.Interface().(time.Time); ok {
+		if format == "" {
+			format = time.RFC3339
+		}
+		return t.Format(format), nil
+	}
+	if m, ok := textMarshaler(val); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+
+	switch val.Kind() {
+	case reflect.String:
+		return val.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(val.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(val.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", val.Type())
+}
+
+// ndjson streams the values of seq as newline-delimited JSON, flushing
+// after each one. It stops when the request context is done and returns
+// its error.
+func (c *Context) ndjson(code int, seq iter.Seq[any]) error {
+	c.Writer.Header().Set("Content-Type", "application/x-ndjson")
+	c.Writer.WriteHeader(code)
+	encoder := json.NewEncoder(c.Writer)
+	for v := range seq {
+		if err := c.Err(); err != nil {
+			return err
+		}
+		if err := encoder.Encode(v); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+	}
+	return c.Err()
+}
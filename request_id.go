@@ -0,0 +1,135 @@
+package vayu
+
+import (
+	"context"
+	"net/http"
+)
+
+// HeaderXRequestID is the default header used to carry request IDs.
+const HeaderXRequestID = "X-Request-ID"
+
+// defaultRequestIDMaxLength bounds inbound request IDs accepted by default.
+const defaultRequestIDMaxLength = 128
+
+// requestIDKey is the context.Context key under which the request ID is stored.
+type requestIDKey struct{}
+
+// RequestIDConfig configures the RequestID middleware.
+type RequestIDConfig struct {
+	// Header is the request and response header carrying the ID.
+	// Defaults to X-Request-ID.
+	Header string
+
+	// Generator creates a new ID when the request has none or an invalid one.
+	// Defaults to a random UUID v4.
+	Generator func() string
+
+	// MaxLength is the maximum accepted length of an inbound ID. Defaults to 128.
+	MaxLength int
+
+	// Validator reports whether an inbound ID may be reused. When nil, IDs
+	// must be at most MaxLength characters from [A-Za-z0-9-_.:+/=].
+	Validator func(id string) bool
+}
+
+// RequestID returns middleware that assigns every request an ID.
+// A valid inbound ID is reused, otherwise a new one is generated. The ID is
+// stored on the Context, added to the request's context.Context, echoed in
+// the response header and attached to the request-scoped logger.
+func RequestID(config RequestIDConfig) HandlerFunc {
+	header := config.Header
+	if header == "" {
+		header = HeaderXRequestID
+	}
+	generate := config.Generator
+	if generate == nil {
+		generate = newRequestID
+	}
+	maxLength := config.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultRequestIDMaxLength
+	}
+	valid := config.Validator
+	if valid == nil {
+		valid = func(id string) bool {
+			return validRequestID(id, maxLength)
+		}
+	}
+
+	return func(c *Context, next NextFunc) {
+		id := c.Request.Header.Get(header)
+		if id == "" || !valid(id) {
+			id = generate()
+		}
+
+		c.requestID = id
+		c.requestIDHeader = header
+		// Rebuild the request-scoped logger with the new ID
+		c.logger = nil
+
+		c.SetContext(context.WithValue(c.baseContext(), requestIDKey{}, id))
+		c.Writer.Header().Set(header, id)
+
+		next()
+	}
+}
+
+// RequestID returns the ID assigned by the RequestID middleware.
+// Without the middleware it falls back to the inbound X-Request-ID header,
+// or "" when that header fails the middleware's default validation.
+func (c *Context) RequestID() string {
+	if c.requestID != "" {
+		return c.requestID
+	}
+	if c.Request == nil {
+		return ""
+	}
+	if id := c.Request.Header.Get(HeaderXRequestID); validRequestID(id, defaultRequestIDMaxLength) {
+		return id
+	}
+	return ""
+}
+
+// PropagateRequestID sets the current request ID on an outbound request,
+// using the same header the RequestID middleware was configured with.
+func (c *Context) PropagateRequestID(req *http.Request) {
+	id := c.RequestID()
+	if id == "" {
+		return
+	}
+	header := c.requestIDHeader
+	if header == "" {
+		header = HeaderXRequestID
+	}
+	req.Header.Set(header, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx by the RequestID
+// middleware, for code that only has access to a context.Context.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// validRequestID reports whether id is non-empty, at most maxLength bytes
+// and made only of characters that are safe to log and echo back.
+func validRequestID(id string, maxLength int) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+		switch {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '-', ch == '_', ch == '.', ch == ':', ch == '+', ch == '/', ch == '=':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random UUID v4 string.
+func newRequestID() string {
+	return NewUUID().String()
+}
@@ -5,11 +5,13 @@ import (
 )
 
 // ResponseWriter is a wrapper around http.ResponseWriter that tracks
-// whether a response has been written yet.
+// whether a response has been written yet, its status and its size.
 type ResponseWriter struct {
 	http.ResponseWriter
 	written bool
 	status  int
+	size    int
+	before  []func()
 }
 
 // NewResponseWriter creates a new ResponseWriter
@@ -24,16 +26,24 @@ func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
 // WriteHeader sets the status code for the response and marks
 // the response as written.
 func (w *ResponseWriter) WriteHeader(code int) {
+	w.runBefore()
 	w.status = code
 	w.written = true
 	w.ResponseWriter.WriteHeader(code)
 }
 
 // Write writes the data to the connection and marks the response
-// as written.
+// as written. Like net/http, writing without calling WriteHeader
+// first implies a 200 OK status.
 func (w *ResponseWriter) Write(b []byte) (int, error) {
+	w.runBefore()
+	if w.status == 0 {
+		w.status = StatusOK
+	}
 	w.written = true
-	return w.ResponseWriter.Write(b)
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
 }
 
 // Written returns whether the response has been written yet.
@@ -45,3 +55,37 @@ func (w *ResponseWriter) Written() bool {
 func (w *ResponseWriter) Status() int {
 	return w.status
 }
+
+// Size returns the number of body bytes written so far.
+func (w *ResponseWriter) Size() int {
+	return w.size
+}
+
+// Before registers fn to run just before the response headers are
+// written, so it can still modify them, e.g. to set a cookie.
+// Functions run in the order they were registered.
+func (w *ResponseWriter) Before(fn func()) {
+	w.before = append(w.before, fn)
+}
+
+// runBefore runs the Before functions once, before the first write.
+func (w *ResponseWriter) runBefore() {
+	if w.written || len(w.before) == 0 {
+		return
+	}
+	hooks := w.before
+	w.before = nil
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+// Flush sends any buffered data to the client, writing a 200 OK header
+// first if none was written. It does nothing if the underlying writer
+// cannot flush.
+func (w *ResponseWriter) Flush() {
+	if !w.written {
+		w.WriteHeader(StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
@@ -4,38 +4,80 @@ import "strings"
 
 type route struct {
 	pattern string
+	parts   []string
 	handler HandlerFunc
+	// chain is the global middleware followed by the handler. It is composed
+	// when the route or middleware is registered, never per request.
+	chain []HandlerFunc
 }
 
 type Router struct {
 	routes map[string][]route
+	// names maps route names to their patterns
+	names map[string]string
 }
 
-func (r *Router) matchRoute(method, path string) (HandlerFunc, map[string]string) {
-	routes := r.routes[method]
-	for _, route := range routes {
-		patternParts := splitPath(route.pattern)
-		pathParts := splitPath(path)
+// add registers a route and composes its handler chain.
+func (r *Router) add(method, pattern string, handler HandlerFunc, middleware []HandlerFunc) {
+	r.routes[method] = append(r.routes[method], route{
+		pattern: pattern,
+		parts:   splitPath(pattern),
+		handler: handler,
+		chain:   buildChain(middleware, handler),
+	})
+}
 
-		if len(patternParts) != len(pathParts) {
-			continue
+// rebuild recomposes every route's handler chain after the global
+// middleware stack changed.
+func (r *Router) rebuild(middleware []HandlerFunc) {
+	for _, routes := range r.routes {
+		for i := range routes {
+			routes[i].chain = buildChain(middleware, routes[i].handler)
 		}
+	}
+}
 
-		params := make(map[string]string)
-		match := true
+// buildChain returns a new slice holding middleware followed by handler.
+// It never shares the middleware slice's backing array.
+func buildChain(middleware []HandlerFunc, handler HandlerFunc) []HandlerFunc {
+	chain := make([]HandlerFunc, 0, len(middleware)+1)
+	chain = append(chain, middleware...)
+	return append(chain, handler)
+}
 
-		for i := range patternParts {
-			if strings.HasPrefix(patternParts[i], ":") {
-				params[patternParts[i][1:]] = pathParts[i]
-			} else if patternParts[i] != pathParts[i] {
-				match = false
-				break
-			}
+// matchRoute finds the first route matching method and path, filling params
+// with its path parameters. params must be empty and is left empty when no
+// route matches.
+func (r *Router) matchRoute(method, path string, params map[string]string) *route {
+	routes := r.routes[method]
+	path = strings.Trim(path, "/")
+	for i := range routes {
+		if routes[i].match(path, params) {
+			return &routes[i]
+		}
+		clear(params)
+	}
+	return nil
+}
+
+// match reports whether the trimmed path matches the route, walking the
+// path segments in place to avoid allocating.
+func (rt *route) match(path string, params map[string]string) bool {
+	for _, part := range rt.parts {
+		if path == "" {
+			return false
+		}
+		segment, rest := path, ""
+		if i := strings.IndexByte(path, '/'); i >= 0 {
+			segment, rest = path[:i], path[i+1:]
 		}
 
-		if match {
-			return route.handler, params
+		if strings.HasPrefix(part, ":") {
+			params[part[1:]] = segment
+		} else if part != segment {
+			return false
 		}
+		path = rest
 	}
-	return nil, nil
+	return path == ""
 }
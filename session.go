@@ -0,0 +1,466 @@
+package vayu
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultSessionCookie is the name of the session cookie used when
+// SessionConfig.CookieName is empty.
+const DefaultSessionCookie = "session"
+
+// DefaultSessionMaxAge is the idle lifetime of a session used when
+// SessionConfig.MaxAge is zero.
+const DefaultSessionMaxAge = 24 * time.Hour
+
+// ErrSessionNotFound is returned by a Store when a session does not exist
+// or has expired.
+var ErrSessionNotFound = errors.New("session not found")
+
+// SessionData is the state a Store persists for a session.
+type SessionData struct {
+	ID      string
+	Values  map[string]any
+	Expires time.Time
+}
+
+// Store persists sessions. The token is the value of the session cookie:
+// server-side stores use the session ID, while CookieStore keeps the whole
+// session in it. Implementations must be safe for concurrent use.
+type Store interface {
+	// Load returns the session for a cookie token, or ErrSessionNotFound.
+	Load(ctx context.Context, token string) (*SessionData, error)
+	// Save persists the session and returns the token to send in the cookie.
+	Save(ctx context.Context, data *SessionData) (string, error)
+	// Delete removes the session with the given ID.
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionConfig configures the Sessions middleware.
+type SessionConfig struct {
+	// Store persists the sessions. It is required.
+	Store Store
+
+	// CookieName is the name of the session cookie. Defaults to "session".
+	CookieName string
+
+	// MaxAge is how long a session lives without requests. Every request
+	// that uses an existing session extends it. Defaults to 24 hours.
+	MaxAge time.Duration
+
+	// Path and Domain scope the session cookie. Path defaults to "/".
+	Path   string
+	Domain string
+
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+
+	// Insecure drops the cookie's Secure attribute so sessions work over
+	// plain HTTP during development.
+	Insecure bool
+}
+
+// Sessions returns middleware that loads the session named by the request's
+// session cookie, makes it available through c.Session, and saves it just
+// before the response is written. New sessions are only stored once a
+// value is set, so anonymous requests do not create sessions.
+func Sessions(config SessionConfig) HandlerFunc {
+	if config.Store == nil {
+		panic("vayu: SessionConfig.Store is required")
+	}
+	if config.CookieName == "" {
+		config.CookieName = DefaultSessionCookie
+	}
+	if config.MaxAge == 0 {
+		config.MaxAge = DefaultSessionMaxAge
+	}
+	if config.Path == "" {
+		config.Path = "/"
+	}
+	if config.SameSite == 0 {
+		config.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(c *Context, next NextFunc) {
+		s := &Session{id: newSessionID(), values: make(map[string]any), isNew: true}
+
+		if token, err := c.Cookie(config.CookieName); err == nil && token != "" {
+			data, err := config.Store.Load(c, token)
+			switch {
+			case err == nil:
+				s.load(data)
+			case !errors.Is(err, ErrSessionNotFound):
+				c.Logger().Error("error loading session", "error", err)
+			}
+			s.hadCookie = true
+		}
+
+		c.session = s
+		c.Writer.Before(func() { saveSession(c, s, config) })
+		next()
+		if !c.Writer.Written() {
+			saveSession(c, s, config)
+		}
+	}
+}
+
+// saveSession persists s and sets or clears the session cookie.
+func saveSession(c *Context, s *Session, config SessionConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saved {
+		return
+	}
+	s.saved = true
+
+	if s.oldID != "" {
+		if err := config.Store.Delete(c, s.oldID); err != nil {
+			c.Logger().Error("error deleting session", "error", err)
+		}
+	}
+
+	if len(s.values) == 0 && len(s.flashes) == 0 && (s.isNew || s.destroyed) {
+		if s.hadCookie {
+			// The attributes must match the cookie's or the browser keeps it
+			c.ClearCookie(sessionCookie(config, ""))
+		}
+		return
+	}
+
+	data := &SessionData{ID: s.id, Values: s.values, Expires: time.Now().Add(config.MaxAge)}
+	if len(s.flashes) > 0 {
+		data.Values = maps.Clone(s.values)
+		data.Values[flashesKey] = s.flashes
+	}
+	token, err := config.Store.Save(c, data)
+	if err != nil {
+		c.Logger().Error("error saving session", "error", err)
+		return
+	}
+
+	cookie := sessionCookie(config, token)
+	cookie.MaxAge = int(config.MaxAge / time.Second)
+	c.SetCookie(cookie)
+}
+
+// sessionCookie returns the session cookie with the configured attributes.
+func sessionCookie(config SessionConfig, value string) *http.Cookie {
+	return &http.Cookie{
+		Name:     config.CookieName,
+		Value:    value,
+		Path:     config.Path,
+		Domain:   config.Domain,
+		HttpOnly: true,
+		Secure:   !config.Insecure,
+		SameSite: config.SameSite,
+	}
+}
+
+// Session returns the request's session, or nil when the Sessions
+// middleware is not installed.
+func (c *Context) Session() *Session {
+	return c.session
+}
+
+// SessionValue retrieves a typed value from the request's session.
+// This provides compile-time type safety through generics.
+// Usage: userID, ok := vayu.SessionValue[string](c, "user_id")
+func SessionValue[T any](c *Context, key string) (T, bool) {
+	var zero T
+	if c.session == nil {
+		return zero, false
+	}
+	val, ok := c.session.Get(key)
+	if !ok {
+		return zero, false
+	}
+	typed, ok := val.(T)
+	return typed, ok
+}
+
+// flashesKey is the reserved session key under which flash messages are stored.
+const flashesKey = "_flashes"
+
+// Session holds the values of one client's session.
+// Changes are saved when the response is written.
+type Session struct {
+	mu        sync.Mutex
+	id        string
+	oldID     string
+	values    map[string]any
+	flashes   map[string][]any
+	isNew     bool
+	hadCookie bool
+	destroyed bool
+	saved     bool
+}
+
+// load fills the session from stored data.
+func (s *Session) load(data *SessionData) {
+	s.id = data.ID
+	s.isNew = false
+	for k, v := range data.Values {
+		if k == flashesKey {
+			s.flashes = decodeFlashes(v)
+			continue
+		}
+		s.values[k] = v
+	}
+}
+
+// decodeFlashes converts stored flash messages back to a map. Stores that
+// serialize sessions, such as CookieStore, return them as map[string]any.
+func decodeFlashes(v any) map[string][]any {
+	switch flashes := v.(type) {
+	case map[string][]any:
+		return maps.Clone(flashes)
+	case map[string]any:
+		result := make(map[string][]any, len(flashes))
+		for k, list := range flashes {
+			if items, ok := list.([]any); ok {
+				result[k] = items
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// ID returns the session ID.
+func (s *Session) ID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.id
+}
+
+// IsNew reports whether the session was created by this request.
+func (s *Session) IsNew() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.isNew
+}
+
+// Get returns the value stored under key.
+func (s *Session) Get(key string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	val, ok := s.values[key]
+	return val, ok
+}
+
+// Set stores a value under key.
+func (s *Session) Set(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+}
+
+// Delete removes the value stored under key.
+func (s *Session) Delete(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.values, key)
+}
+
+// Flash adds a message that is kept until it is read with Flashes,
+// typically on the next request.
+func (s *Session) Flash(key string, value any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flashes == nil {
+		s.flashes = make(map[string][]any)
+	}
+	s.flashes[key] = append(s.flashes[key], value)
+}
+
+// Flashes returns and removes the flash messages stored under key.
+func (s *Session) Flashes(key string) []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	messages := s.flashes[key]
+	delete(s.flashes, key)
+	return messages
+}
+
+// Regenerate gives the session a new ID and deletes the old one from the
+// store, keeping its values. Call it when a user logs in to prevent
+// session fixation.
+func (s *Session) Regenerate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.regenerate()
+}
+
+func (s *Session) regenerate() {
+	if !s.isNew && s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = newSessionID()
+}
+
+// Destroy removes every value, deletes the session from the store and
+// clears the cookie. Values set afterwards start a new session.
+func (s *Session) Destroy() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.values)
+	s.flashes = nil
+	s.regenerate()
+	s.destroyed = true
+}
+
+// newSessionID returns a random 256-bit session ID.
+func newSessionID() string {
+	var b [32]byte
+	readRandom(b[:])
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// MemoryStore is a Store that keeps sessions in memory. Expired sessions
+// are removed periodically. It suits single-instance deployments and tests.
+type MemoryStore struct {
+	mu       sync.Mutex
+	sessions map[string]*SessionData
+	stop     chan struct{}
+	once     sync.Once
+}
+
+// NewMemoryStore creates a MemoryStore that removes expired sessions every
+// sweepInterval, or every minute if it is zero. Call Close to stop sweeping.
+func NewMemoryStore(sweepInterval time.Duration) *MemoryStore {
+	if sweepInterval <= 0 {
+		sweepInterval = time.Minute
+	}
+	m := &MemoryStore{
+		sessions: make(map[string]*SessionData),
+		stop:     make(chan struct{}),
+	}
+	go m.sweepEvery(sweepInterval)
+	return m
+}
+
+func (m *MemoryStore) sweepEvery(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Sweep()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Load implements Store.
+func (m *MemoryStore) Load(_ context.Context, token string) (*SessionData, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data, ok := m.sessions[token]
+	if !ok || time.Now().After(data.Expires) {
+		return nil, ErrSessionNotFound
+	}
+	return &SessionData{ID: data.ID, Values: maps.Clone(data.Values), Expires: data.Expires}, nil
+}
+
+// Save implements Store.
+func (m *MemoryStore) Save(_ context.Context, data *SessionData) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sessions[data.ID] = &SessionData{ID: data.ID, Values: maps.Clone(data.Values), Expires: data.Expires}
+	return data.ID, nil
+}
+
+// Delete implements Store.
+func (m *MemoryStore) Delete(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, id)
+	return nil
+}
+
+// Sweep removes expired sessions.
+func (m *MemoryStore) Sweep() {
+	now := time.Now()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, data := range m.sessions {
+		if now.After(data.Expires) {
+			delete(m.sessions, id)
+		}
+	}
+}
+
+// Len returns the number of stored sessions, including expired ones that
+// have not been swept yet.
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.sessions)
+}
+
+// Close stops the background sweeping.
+func (m *MemoryStore) Close() {
+	m.once.Do(func() { close(m.stop) })
+}
+
+// CookieStore is a Store that keeps the whole session in the session
+// cookie, encrypted with CookieKeys. Values are serialized as JSON, so they
+// come back as JSON types (numbers as float64, objects as map[string]any).
+// Cookies are limited to about 4KB, and Delete cannot revoke a cookie the
+// client already holds before it expires.
+type CookieStore struct {
+	keys *CookieKeys
+}
+
+// NewCookieStore creates a CookieStore that encrypts sessions with keys.
+func NewCookieStore(keys *CookieKeys) *CookieStore {
+	return &CookieStore{keys: keys}
+}
+
+// cookieStoreName is authenticated together with every encrypted session.
+const cookieStoreName = "vayu-session"
+
+type cookieSession struct {
+	ID      string         `json:"id"`
+	Values  map[string]any `json:"values,omitempty"`
+	Expires int64          `json:"exp"`
+}
+
+// Load implements Store.
+func (s *CookieStore) Load(_ context.Context, token string) (*SessionData, error) {
+	plain, err := s.keys.Decrypt(cookieStoreName, token)
+	if err != nil {
+		return nil, ErrSessionNotFound
+	}
+	var cs cookieSession
+	if err := json.Unmarshal([]byte(plain), &cs); err != nil {
+		return nil, ErrSessionNotFound
+	}
+	expires := time.Unix(cs.Expires, 0)
+	if time.Now().After(expires) {
+		return nil, ErrSessionNotFound
+	}
+	return &SessionData{ID: cs.ID, Values: cs.Values, Expires: expires}, nil
+}
+
+// Save implements Store.
+func (s *CookieStore) Save(_ context.Context, data *SessionData) (string, error) {
+	plain, err := json.Marshal(cookieSession{ID: data.ID, Values: data.Values, Expires: data.Expires.Unix()})
+	if err != nil {
+		return "", err
+	}
+	return s.keys.Encrypt(cookieStoreName, string(plain)), nil
+}
+
+// Delete implements Store. The session lives only in the client's cookie,
+// which the middleware clears, so there is nothing to delete.
+func (s *CookieStore) Delete(context.Context, string) error {
+	return nil
+}
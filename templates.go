@@ -0,0 +1,321 @@
+package vayu
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+	"text/template/parse"
+	"time"
+)
+
+var (
+	// ErrNoTemplates is returned by Render when the app has no templates;
+	// see App.LoadTemplates.
+	ErrNoTemplates = errors.New("no templates loaded")
+
+	// ErrTemplateNotFound is wrapped by the error Render returns for an
+	// unknown template name.
+	ErrTemplateNotFound = errors.New("template not found")
+)
+
+// TemplateConfig configures the HTML templates loaded by App.LoadTemplates.
+//
+// Every file with the extension under Root is a template named by its path
+// without the extension, such as "users/show". Files in the shared
+// directories, by default "layouts" and "partials", are available to every
+// page. Each page is parsed separately, so pages can define blocks with the
+// same names without clashing.
+type TemplateConfig struct {
+	// FS holds the templates, e.g. an embed.FS or os.DirFS("views").
+	FS fs.FS
+
+	// Root is the directory within FS containing the templates.
+	// Defaults to ".".
+	Root string
+
+	// Extension of template files. Defaults to ".html".
+	Extension string
+
+	// SharedDirs are the directories under Root holding layouts and partials.
+	// Defaults to "layouts" and "partials".
+	SharedDirs []string
+
+	// Layout is the template Render wraps pages in, e.g. "layouts/main".
+	// The layout includes the page with {{template "content" .}}.
+	// Empty renders pages on their own.
+	Layout string
+
+	// Funcs are made available to every template.
+	Funcs template.FuncMap
+
+	// Reload reparses the templates when a file changes, for development.
+	// It stats every file when it checks, so leave it off in production.
+	Reload bool
+
+	// ReloadInterval is the minimum time between checks for changed files
+	// when Reload is set. Defaults to one second; a negative value checks
+	// on every render.
+	ReloadInterval time.Duration
+}
+
+// LoadTemplates parses the templates described by config and makes them
+// available to Context.Render, replacing any loaded before.
+//
+//	//go:embed views
+//	var views embed.FS
+//
+//	err := app.LoadTemplates(vayu.TemplateConfig{FS: views, Root: "views", Layout: "layouts/main"})
+func (a *App) LoadTemplates(config TemplateConfig) error {
+	if config.FS == nil {
+		return errors.New("TemplateConfig.FS is required")
+	}
+	config.Root = path.Clean("./" + config.Root)
+	if config.Extension == "" {
+		config.Extension = ".html"
+	}
+	if config.SharedDirs == nil {
+		config.SharedDirs = []string{"layouts", "partials"}
+	}
+	if config.ReloadInterval == 0 {
+		config.ReloadInterval = time.Second
+	}
+
+	engine := &templateEngine{config: config}
+	if err := engine.load(); err != nil {
+		return err
+	}
+	a.templates = engine
+	return nil
+}
+
+// Render executes the named template, wrapped in the configured layout,
+// and sends the result as text/html with the given status code.
+// Nothing is written if execution fails.
+func (c *Context) Render(code int, name string, data any) error {
+	engine, err := c.templateEngine()
+	if err != nil {
+		return err
+	}
+	return c.RenderLayout(code, engine.config.Layout, name, data)
+}
+
+// RenderLayout is like Render but wraps the page in the given layout
+// instead of the configured one. An empty layout renders the page alone.
+func (c *Context) RenderLayout(code int, layout, name string, data any) error {
+	engine, err := c.templateEngine()
+	if err != nil {
+		return err
+	}
+	page, err := engine.lookup(name)
+	if err != nil {
+		return err
+	}
+
+	entry := name
+	if layout != "" {
+		if page.Lookup(layout) == nil {
+			return fmt.Errorf("layout %q: %w", layout, ErrTemplateNotFound)
+		}
+		entry = layout
+	}
+
+	var buf bytes.Buffer
+	if err := page.ExecuteTemplate(&buf, entry, data); err != nil {
+		return err
+	}
+	_, err = c.HTML(code, buf.String())
+	return err
+}
+
+// ViewRenderer returns a Renderer that renders the named app template with
+// Render, for offering HTML pages to Negotiate.
+func ViewRenderer(name string) Renderer {
+	return func(c *Context, code int, data any) error {
+		return c.Render(code, name, data)
+	}
+}
+
+// templateEngine returns the app's templates.
+func (c *Context) templateEngine() (*templateEngine, error) {
+	if c.app == nil || c.app.templates == nil {
+		return nil, ErrNoTemplates
+	}
+	return c.app.templates, nil
+}
+
+// templateEngine holds a parsed template set per page.
+type templateEngine struct {
+	config TemplateConfig
+
+	mu    sync.RWMutex
+	pages map[string]*template.Template
+
+	// reloadMu serializes reload checks so only one render reparses
+	reloadMu  sync.Mutex
+	signature uint64
+	checked   time.Time
+}
+
+// lookup returns the template set of a page, reparsing first if Reload is
+// set and the files changed.
+func (e *templateEngine) lookup(name string) (*template.Template, error) {
+	if e.config.Reload {
+		if err := e.reloadIfChanged(); err != nil {
+			return nil, err
+		}
+	}
+
+	e.mu.RLock()
+	page, ok := e.pages[name]
+	e.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("template %q: %w", name, ErrTemplateNotFound)
+	}
+	return page, nil
+}
+
+// reloadIfChanged reparses the templates if any file was added, removed or
+// modified since they were last parsed. It checks at most once per
+// ReloadInterval, and renders waiting on a check use its result.
+func (e *templateEngine) reloadIfChanged() error {
+	e.reloadMu.Lock()
+	defer e.reloadMu.Unlock()
+	if time.Since(e.checked) < e.config.ReloadInterval {
+		return nil
+	}
+	e.checked = time.Now()
+
+	files, err := e.files()
+	if err != nil {
+		return err
+	}
+	signature, err := e.fileSignature(files)
+	if err != nil {
+		return err
+	}
+	if signature == e.signature {
+		return nil
+	}
+	return e.load()
+}
+
+// load parses every template and replaces the page sets. Callers other
+// than LoadTemplates must hold reloadMu.
+func (e *templateEngine) load() error {
+	files, err := e.files()
+	if err != nil {
+		return err
+	}
+	signature, err := e.fileSignature(files)
+	if err != nil {
+		return err
+	}
+
+	base := template.New("").Funcs(e.config.Funcs)
+	var pageFiles []string
+	for _, file := range files {
+		if !e.isShared(file) {
+			pageFiles = append(pageFiles, file)
+			continue
+		}
+		if err := e.parse(base, file); err != nil {
+			return err
+		}
+	}
+
+	pages := make(map[string]*template.Template, len(pageFiles))
+	for _, file := range pageFiles {
+		set, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		if err := e.parse(set, file); err != nil {
+			return err
+		}
+
+		// The page body is what layouts include as "content"
+		name := e.templateName(file)
+		if page := set.Lookup(name); page.Tree != nil && !parse.IsEmptyTree(page.Tree.Root) {
+			if _, err := set.AddParseTree("content", page.Tree.Copy()); err != nil {
+				return fmt.Errorf("template %s: %w", file, err)
+			}
+		}
+		pages[name] = set
+	}
+
+	e.mu.Lock()
+	e.pages = pages
+	e.mu.Unlock()
+	e.signature = signature
+	return nil
+}
+
+// parse adds the template in file to set.
+func (e *templateEngine) parse(set *template.Template, file string) error {
+	content, err := fs.ReadFile(e.config.FS, file)
+	if err != nil {
+		return err
+	}
+	if _, err := set.New(e.templateName(file)).Parse(string(content)); err != nil {
+		return fmt.Errorf("template %s: %w", file, err)
+	}
+	return nil
+}
+
+// files returns the paths of the template files, in lexical order.
+func (e *templateEngine) files() ([]string, error) {
+	var files []string
+	err := fs.WalkDir(e.config.FS, e.config.Root, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && strings.HasSuffix(file, e.config.Extension) {
+			files = append(files, file)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("loading templates: %w", err)
+	}
+	return files, nil
+}
+
+// fileSignature hashes the names, sizes and modification times of files.
+func (e *templateEngine) fileSignature(files []string) (uint64, error) {
+	h := fnv.New64a()
+	for _, file := range files {
+		info, err := fs.Stat(e.config.FS, file)
+		if err != nil {
+			return 0, err
+		}
+		fmt.Fprintf(h, "%s\x00%d\x00%d\x00", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return h.Sum64(), nil
+}
+
+// templateName returns the name of the template in file: its path under
+// Root without the extension.
+func (e *templateEngine) templateName(file string) string {
+	name := strings.TrimSuffix(file, e.config.Extension)
+	if e.config.Root != "." {
+		name = strings.TrimPrefix(name, e.config.Root+"/")
+	}
+	return name
+}
+
+// isShared reports whether file is in one of the shared directories.
+func (e *templateEngine) isShared(file string) bool {
+	name := e.templateName(file)
+	for _, dir := range e.config.SharedDirs {
+		if strings.HasPrefix(name, strings.Trim(dir, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,160 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+// newAccessLogApp creates an app with the access log middleware and a few routes
+func newAccessLogApp(config vayu.AccessLogConfig) *vayu.App {
+	app := vayu.New()
+	app.Use(vayu.AccessLog(config))
+	app.GET("/users/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Send(vayu.StatusCreated, "hello")
+	})
+	app.GET("/healthz", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Send(vayu.StatusOK, "ok")
+	})
+	return app
+}
+
+func TestAccessLogCommonFormat(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Output: &buf})
+
+	req := httptest.NewRequest("GET", "/users/42?x=1", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	pattern := `^10\.0\.0\.1 - - \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /users/42\?x=1 HTTP/1\.1" 201 5\n$`
+	if !regexp.MustCompile(pattern).MatchString(buf.String()) {
+		t.Errorf("Unexpected common log line: %q", buf.String())
+	}
+}
+
+func TestAccessLogCombinedFormat(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Format: vayu.AccessLogCombined, Output: &buf})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	req.Header.Set("Referer", "https://example.com/")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	if !strings.HasSuffix(line, `201 5 "https://example.com/" "test-agent/1.0"`+"\n") {
+		t.Errorf("Unexpected combined log line: %q", line)
+	}
+}
+
+func TestAccessLogEscapesClientFields(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Format: vayu.AccessLogCombined, Output: &buf})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.SetBasicAuth("ada\n6.6.6.6 - root", "secret")
+	req.Header.Set("User-Agent", `agent "quoted" \ é`)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	line := buf.String()
+	if strings.Count(line, "\n") != 1 {
+		t.Fatalf("Expected a single log line, got %q", line)
+	}
+	if !strings.Contains(line, ` - ada\x0a6.6.6.6 - root [`) {
+		t.Errorf("Expected an escaped user, got %q", line)
+	}
+	if !strings.HasSuffix(line, `"-" "agent \"quoted\" \\ \xc3\xa9"`+"\n") {
+		t.Errorf("Expected an escaped user agent, got %q", line)
+	}
+}
+
+func TestAccessLogJSONFormat(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Format: vayu.AccessLogJSON, Output: &buf})
+
+	req := httptest.NewRequest("GET", "/users/7", nil)
+	req.Header.Set("User-Agent", "test-agent/1.0")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entry vayu.AccessLogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to decode JSON log line %q: %v", buf.String(), err)
+	}
+
+	if entry.Status != vayu.StatusCreated {
+		t.Errorf("Expected status %d, got %d", vayu.StatusCreated, entry.Status)
+	}
+	if entry.Size != 5 {
+		t.Errorf("Expected size 5, got %d", entry.Size)
+	}
+	if entry.Route != "/users/:id" {
+		t.Errorf("Expected route '/users/:id', got '%s'", entry.Route)
+	}
+	if entry.Path != "/users/7" {
+		t.Errorf("Expected path '/users/7', got '%s'", entry.Path)
+	}
+	if entry.UserAgent != "test-agent/1.0" {
+		t.Errorf("Expected user agent 'test-agent/1.0', got '%s'", entry.UserAgent)
+	}
+	if entry.RemoteIP != "192.0.2.1" {
+		t.Errorf("Expected remote IP '192.0.2.1', got '%s'", entry.RemoteIP)
+	}
+}
+
+func TestAccessLogSlogFormat(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Format: vayu.AccessLogSlog})
+	app.SetLogger(slog.New(slog.NewTextHandler(&buf, nil)))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/9", nil))
+
+	line := buf.String()
+	for _, want := range []string{"msg=access", "route=/users/:id", "status=201", "size=5", "latency="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("Expected slog access line to contain %q, got %q", want, line)
+		}
+	}
+}
+
+func TestAccessLogSkip(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{
+		Output:    &buf,
+		SkipPaths: []string{"/healthz"},
+		Skip: func(c *vayu.Context) bool {
+			return c.Params["id"] == "secret"
+		},
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/secret", nil))
+	if buf.Len() != 0 {
+		t.Errorf("Expected skipped requests not to be logged, got %q", buf.String())
+	}
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	if buf.Len() == 0 {
+		t.Error("Expected request to be logged")
+	}
+}
+
+func TestResponseWriterSize(t *testing.T) {
+	w := vayu.NewResponseWriter(httptest.NewRecorder())
+
+	w.Write([]byte("hello "))
+	w.Write([]byte("world"))
+
+	if w.Size() != 11 {
+		t.Errorf("Expected size 11, got %d", w.Size())
+	}
+	if w.Status() != vayu.StatusOK {
+		t.Errorf("Expected implicit status %d, got %d", vayu.StatusOK, w.Status())
+	}
+}
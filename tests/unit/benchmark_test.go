@@ -0,0 +1,76 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+// discardWriter is a minimal http.ResponseWriter that keeps the benchmarks
+// from measuring httptest.ResponseRecorder allocations.
+type discardWriter struct {
+	header http.Header
+}
+
+func (w *discardWriter) Header() http.Header         { return w.header }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func newBenchmarkApp(middlewares int) *vayu.App {
+	app := vayu.New()
+	for i := 0; i < middlewares; i++ {
+		app.Use(func(c *vayu.Context, next vayu.NextFunc) {
+			next()
+		})
+	}
+
+	body := []byte("ok")
+	handler := func(c *vayu.Context, next vayu.NextFunc) {
+		c.Writer.WriteHeader(vayu.StatusOK)
+		c.Writer.Write(body)
+	}
+	app.GET("/", handler)
+	app.GET("/users", handler)
+	app.GET("/users/:id", handler)
+	app.GET("/users/:id/posts/:post", handler)
+	return app
+}
+
+func benchmarkServeHTTP(b *testing.B, app *vayu.App, path string) {
+	req := httptest.NewRequest("GET", path, nil)
+	w := &discardWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		app.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkServeHTTPStatic(b *testing.B) {
+	benchmarkServeHTTP(b, newBenchmarkApp(0), "/users")
+}
+
+func BenchmarkServeHTTPParams(b *testing.B) {
+	benchmarkServeHTTP(b, newBenchmarkApp(0), "/users/42/posts/7")
+}
+
+func BenchmarkServeHTTPMiddleware(b *testing.B) {
+	benchmarkServeHTTP(b, newBenchmarkApp(5), "/users/42")
+}
+
+func BenchmarkServeHTTPNotFound(b *testing.B) {
+	app := newBenchmarkApp(0)
+	app.SetNotFoundHandler(func(c *vayu.Context, next vayu.NextFunc) {
+		c.Writer.WriteHeader(vayu.StatusNotFound)
+	})
+	benchmarkServeHTTP(b, app, "/missing/path")
+}
+
+func BenchmarkServeHTTPNoTimeout(b *testing.B) {
+	app := newBenchmarkApp(5)
+	app.RequestTimeout = 0
+	benchmarkServeHTTP(b, app, "/users/42")
+}
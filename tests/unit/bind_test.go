@@ -0,0 +1,152 @@
+package unit
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type createOrderRequest struct {
+	StoreID  int      `path:"store"`
+	DryRun   bool     `query:"dry_run"`
+	Tenant   string   `header:"X-Tenant-ID" required:"true"`
+	Session  string   `cookie:"session"`
+	Item     string   `json:"item" form:"item"`
+	Quantity int      `json:"quantity" form:"quantity"`
+	Tags     []string `json:"tags" form:"tag"`
+}
+
+func TestBindMergesSources(t *testing.T) {
+	app := vayu.New()
+
+	var got createOrderRequest
+	var bindErr error
+	app.POST("/stores/:store/orders", func(c *vayu.Context, next vayu.NextFunc) {
+		got, bindErr = vayu.Bind[createOrderRequest](c)
+	})
+
+	req := httptest.NewRequest("POST", "/stores/12/orders?dry_run=true",
+		strings.NewReader(`{"item":"book","quantity":2,"tags":["gift"]}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc"})
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NoError(t, bindErr) {
+		assert.Equal(t, createOrderRequest{
+			StoreID:  12,
+			DryRun:   true,
+			Tenant:   "acme",
+			Session:  "abc",
+			Item:     "book",
+			Quantity: 2,
+			Tags:     []string{"gift"},
+		}, got)
+	}
+}
+
+func TestBindForm(t *testing.T) {
+	app := vayu.New()
+
+	var got createOrderRequest
+	var bindErr error
+	app.POST("/stores/:store/orders", func(c *vayu.Context, next vayu.NextFunc) {
+		got, bindErr = vayu.Bind[createOrderRequest](c)
+	})
+
+	form := url.Values{"item": {"pen"}, "quantity": {"5"}, "tag": {"office", "blue"}}
+	req := httptest.NewRequest("POST", "/stores/3/orders", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Tenant-ID", "acme")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.NoError(t, bindErr) {
+		assert.Equal(t, 3, got.StoreID)
+		assert.Equal(t, "pen", got.Item)
+		assert.Equal(t, 5, got.Quantity)
+		assert.Equal(t, []string{"office", "blue"}, got.Tags)
+	}
+}
+
+func TestBindPrecedence(t *testing.T) {
+	app := vayu.New()
+
+	type Lookup struct {
+		ID string `path:"id" query:"id" header:"X-ID"`
+		// Path parameters are required, so fields only optionally in the
+		// route should use a lower-precedence source
+		Version string `query:"v" header:"X-Version"`
+	}
+
+	var got Lookup
+	app.GET("/items/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		got, _ = vayu.Bind[Lookup](c)
+	})
+
+	req := httptest.NewRequest("GET", "/items/from-path?id=from-query&v=2", nil)
+	req.Header.Set("X-ID", "from-header")
+	req.Header.Set("X-Version", "3")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "from-path", got.ID)
+	assert.Equal(t, "2", got.Version)
+
+	req = httptest.NewRequest("GET", "/items/x", nil)
+	req.Header.Set("X-Version", "3")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "3", got.Version)
+}
+
+func TestBindAggregatesErrors(t *testing.T) {
+	app := vayu.New()
+
+	var bindErr error
+	app.POST("/stores/:store/orders", func(c *vayu.Context, next vayu.NextFunc) {
+		_, bindErr = vayu.Bind[createOrderRequest](c)
+	})
+
+	req := httptest.NewRequest("POST", "/stores/main/orders?dry_run=maybe",
+		strings.NewReader(`{"item":"book","quantity":"two"}`))
+	req.Header.Set("Content-Type", "application/json")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	var be *vayu.BindingError
+	if !assert.True(t, errors.As(bindErr, &be)) {
+		return
+	}
+	assert.Len(t, be.Errors, 4)
+
+	sources := make([]string, len(be.Errors))
+	for i, fe := range be.Errors {
+		sources[i] = fe.Source + ":" + fe.Key
+	}
+	assert.ElementsMatch(t, []string{"json:quantity", "path:store", "query:dry_run", "header:X-Tenant-ID"}, sources)
+	assert.True(t, errors.Is(bindErr, vayu.ErrFieldMissing))
+	assert.Contains(t, bindErr.Error(), "JSON field quantity: cannot convert string to int")
+	assert.Contains(t, bindErr.Error(), "required header X-Tenant-ID missing")
+}
+
+func TestMustBindRespondsBadRequest(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+
+	app.POST("/stores/:store/orders", func(c *vayu.Context, next vayu.NextFunc) {
+		order := vayu.MustBind[createOrderRequest](c)
+		c.OK(order)
+	})
+
+	req := httptest.NewRequest("POST", "/stores/1/orders", strings.NewReader(`{"item":`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Tenant-ID", "acme")
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	assert.Equal(t, vayu.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "json body: unexpected EOF")
+}
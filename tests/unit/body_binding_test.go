@@ -0,0 +1,274 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type signupBody struct {
+	Name   string   `json:"name" xml:"name" form:"name" required:"true"`
+	Age    int      `json:"age" xml:"age" form:"age"`
+	Topics []string `json:"topics" xml:"topic" form:"topic"`
+}
+
+func bindBodyApp(t *testing.T, got *signupBody, bindErr *error) *vayu.App {
+	t.Helper()
+	app := vayu.New()
+	app.POST("/signup", func(c *vayu.Context, next vayu.NextFunc) {
+		*got, *bindErr = vayu.BindBody[signupBody](c)
+	})
+	return app
+}
+
+func TestBindBodyDispatchesOnContentType(t *testing.T) {
+	want := signupBody{Name: "Ada", Age: 36, Topics: []string{"math", "engines"}}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json; charset=utf-8", `{"name":"Ada","age":36,"topics":["math","engines"]}`},
+		{"json suffix", "application/vnd.api+json", `{"name":"Ada","age":36,"topics":["math","engines"]}`},
+		{"no content type", "", `{"name":"Ada","age":36,"topics":["math","engines"]}`},
+		{"xml", "application/xml", `<signup><name>Ada</name><age>36</age><topic>math</topic><topic>engines</topic></signup>`},
+		{"text xml", "text/xml", `<signup><name>Ada</name><age>36</age><topic>math</topic><topic>engines</topic></signup>`},
+		{"form", "application/x-www-form-urlencoded", url.Values{"name": {"Ada"}, "age": {"36"}, "topic": {"math", "engines"}}.Encode()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got signupBody
+			var bindErr error
+			app := bindBodyApp(t, &got, &bindErr)
+
+			req := httptest.NewRequest("POST", "/signup", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			app.ServeHTTP(httptest.NewRecorder(), req)
+
+			assert.NoError(t, bindErr)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestBindBodyMultipartFiles(t *testing.T) {
+	type upload struct {
+		Title       string                  `form:"title"`
+		Avatar      *multipart.FileHeader   `form:"avatar" required:"true"`
+		Attachments []*multipart.FileHeader `form:"attachments"`
+	}
+
+	app := vayu.New()
+	var got upload
+	var bindErr error
+	app.POST("/upload", func(c *vayu.Context, next vayu.NextFunc) {
+		got, bindErr = vayu.BindBody[upload](c)
+	})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("title", "holiday")
+	part, _ := writer.CreateFormFile("avatar", "me.png")
+	part.Write([]byte("png-bytes"))
+	for _, name := range []string{"a.txt", "b.txt"} {
+		part, _ = writer.CreateFormFile("attachments", name)
+		part.Write([]byte(name))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if !assert.NoError(t, bindErr) {
+		return
+	}
+	assert.Equal(t, "holiday", got.Title)
+	if assert.NotNil(t, got.Avatar) {
+		assert.Equal(t, "me.png", got.Avatar.Filename)
+		f, err := got.Avatar.Open()
+		if assert.NoError(t, err) {
+			data, _ := io.ReadAll(f)
+			f.Close()
+			assert.Equal(t, "png-bytes", string(data))
+		}
+	}
+	if assert.Len(t, got.Attachments, 2) {
+		assert.Equal(t, "a.txt", got.Attachments[0].Filename)
+		assert.Equal(t, "b.txt", got.Attachments[1].Filename)
+	}
+
+	// A missing required file is reported like any other field
+	body.Reset()
+	writer = multipart.NewWriter(body)
+	writer.WriteField("title", "empty")
+	writer.Close()
+	req = httptest.NewRequest("POST", "/upload", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.EqualError(t, bindErr, "required form field avatar missing")
+}
+
+func TestBindBodyMultipartTooLarge(t *testing.T) {
+	type upload struct {
+		Avatar *multipart.FileHeader `form:"avatar"`
+	}
+
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 1024})
+	app.POST("/upload", func(c *vayu.Context, next vayu.NextFunc) {
+		c.OK(vayu.MustBindBody[upload](c))
+	})
+	app.POST("/file", func(c *vayu.Context, next vayu.NextFunc) {
+		_, _, err := c.FormFile("avatar")
+		var httpErr *vayu.HTTPError
+		if assert.ErrorAs(t, err, &httpErr) {
+			c.Writer.WriteHeader(httpErr.Code)
+		}
+	})
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, _ := writer.CreateFormFile("avatar", "big.png")
+	part.Write(bytes.Repeat([]byte("x"), 4096))
+	writer.Close()
+
+	for _, path := range []string{"/upload", "/file"} {
+		for name, reader := range map[string]io.Reader{
+			"declared length": bytes.NewReader(body.Bytes()),
+			"streamed":        struct{ io.Reader }{bytes.NewReader(body.Bytes())},
+		} {
+			req := httptest.NewRequest("POST", path, reader)
+			req.Header.Set("Content-Type", writer.FormDataContentType())
+			resp := httptest.NewRecorder()
+			app.ServeHTTP(resp, req)
+			assert.Equal(t, vayu.StatusRequestEntityTooLarge, resp.Code, path+" "+name)
+		}
+	}
+}
+
+func TestBindBodyUnsupportedMediaType(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.POST("/signup", func(c *vayu.Context, next vayu.NextFunc) {
+		body := vayu.MustBindBody[signupBody](c)
+		c.OK(body)
+	})
+
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader("name=Ada"))
+	req.Header.Set("Content-Type", "text/plain")
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	assert.Equal(t, vayu.StatusUnsupportedMediaType, resp.Code)
+	assert.Contains(t, resp.Body.String(), "unsupported media type text/plain")
+}
+
+func TestBindBodyMalformed(t *testing.T) {
+	var got signupBody
+	var bindErr error
+	app := bindBodyApp(t, &got, &bindErr)
+
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader(`<signup><name>Ada</signup>`))
+	req.Header.Set("Content-Type", "application/xml")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	var be *vayu.BindingError
+	if assert.True(t, errors.As(bindErr, &be)) {
+		assert.Equal(t, "xml", be.Errors[0].Source)
+		assert.Equal(t, vayu.StatusBadRequest, be.StatusCode())
+	}
+}
+
+func TestBindBodyXMLTooLarge(t *testing.T) {
+	var got signupBody
+	var bindErr error
+	app := bindBodyApp(t, &got, &bindErr)
+	app.SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 64})
+
+	body := "<signup><name>" + strings.Repeat("a", 100) + "</name></signup>"
+	for name, reader := range map[string]io.Reader{
+		"declared length": strings.NewReader(body),
+		"streamed":        struct{ io.Reader }{strings.NewReader(body)},
+	} {
+		bindErr = nil
+		req := httptest.NewRequest("POST", "/signup", reader)
+		req.Header.Set("Content-Type", "application/xml")
+		app.ServeHTTP(httptest.NewRecorder(), req)
+
+		var httpErr *vayu.HTTPError
+		if assert.True(t, errors.As(bindErr, &httpErr), name) {
+			assert.Equal(t, vayu.StatusRequestEntityTooLarge, httpErr.Code, name)
+		}
+	}
+}
+
+func TestBindBodyTooLargeClosesConnection(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 64})
+	app.POST("/signup", func(c *vayu.Context, next vayu.NextFunc) {
+		c.OK(vayu.MustBindBody[signupBody](c))
+	})
+	server := httptest.NewServer(app)
+	defer server.Close()
+
+	// A body of unknown length is only caught while it is read
+	body := struct{ io.Reader }{strings.NewReader("<signup><name>" + strings.Repeat("a", 1000) + "</name></signup>")}
+	resp, err := http.Post(server.URL+"/signup", "application/xml", body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, vayu.StatusRequestEntityTooLarge, resp.StatusCode)
+	assert.True(t, resp.Close, "expected the server to close the connection")
+}
+
+func TestRegisterBodyDecoder(t *testing.T) {
+	// A toy "key:value" line format
+	vayu.RegisterBodyDecoder("text/x-signup", func(c *vayu.Context, dest any) error {
+		data, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return err
+		}
+		s := dest.(*signupBody)
+		for _, line := range strings.Split(string(data), "\n") {
+			if k, v, ok := strings.Cut(line, ":"); ok && k == "name" {
+				s.Name = v
+			}
+		}
+		return nil
+	})
+	defer vayu.RegisterBodyDecoder("text/x-signup", nil)
+
+	var got signupBody
+	var bindErr error
+	app := bindBodyApp(t, &got, &bindErr)
+
+	req := httptest.NewRequest("POST", "/signup", strings.NewReader("name:Grace"))
+	req.Header.Set("Content-Type", "text/x-signup")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.NoError(t, bindErr)
+	assert.Equal(t, "Grace", got.Name)
+
+	vayu.RegisterBodyDecoder("text/x-signup", nil)
+	req = httptest.NewRequest("POST", "/signup", strings.NewReader("name:Grace"))
+	req.Header.Set("Content-Type", "text/x-signup")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.True(t, errors.Is(bindErr, vayu.ErrUnsupportedMediaType))
+}
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+func TestContextResetBetweenRequests(t *testing.T) {
+	app := vayu.New()
+
+	app.GET("/a/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Set("seen", c.Params["id"])
+		c.Stop()
+		c.Send(vayu.StatusOK, "a")
+	})
+
+	var leaked []string
+	app.GET("/b", func(c *vayu.Context, next vayu.NextFunc) {
+		if _, ok := c.Get("seen"); ok {
+			leaked = append(leaked, "store")
+		}
+		if len(c.Params) != 0 {
+			leaked = append(leaked, "params")
+		}
+		if c.Stopped {
+			leaked = append(leaked, "stopped")
+		}
+		if c.Writer.Written() || c.Writer.Size() != 0 {
+			leaked = append(leaked, "writer")
+		}
+		c.Send(vayu.StatusOK, "b")
+	})
+
+	for i := 0; i < 20; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/a/1", nil))
+		w := httptest.NewRecorder()
+		app.ServeHTTP(w, httptest.NewRequest("GET", "/b", nil))
+		if w.Body.String() != "b" {
+			t.Fatalf("Expected body 'b', got '%s'", w.Body.String())
+		}
+	}
+
+	if len(leaked) != 0 {
+		t.Errorf("Expected pooled context to be reset, leaked state: %v", leaked)
+	}
+}
+
+func TestContextCopy(t *testing.T) {
+	app := vayu.New()
+
+	copies := make(chan *vayu.Context, 1)
+	app.GET("/users/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Set("user", "alice")
+		copies <- c.Copy()
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+	cp := <-copies
+
+	// Serve another request so the pooled context is reused
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/2", nil))
+
+	if cp.Params["id"] != "1" {
+		t.Errorf("Expected copied param id '1', got '%s'", cp.Params["id"])
+	}
+	if user, _ := vayu.GetValue[string](cp, "user"); user != "alice" {
+		t.Errorf("Expected copied store value 'alice', got '%s'", user)
+	}
+	if cp.RoutePattern() != "/users/:id" {
+		t.Errorf("Expected copied route '/users/:id', got '%s'", cp.RoutePattern())
+	}
+}
+
+func TestMiddlewareAddedAfterRoutes(t *testing.T) {
+	app := vayu.New()
+
+	app.GET("/late", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Send(vayu.StatusOK, "handler")
+	})
+
+	var called bool
+	app.Use(func(c *vayu.Context, next vayu.NextFunc) {
+		called = true
+		next()
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/late", nil))
+
+	if !called {
+		t.Error("Expected middleware registered after the route to run")
+	}
+}
@@ -1,10 +1,13 @@
 package unit
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"github.com/kaushiksamanta/vayu"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestContextSend(t *testing.T) {
@@ -173,3 +176,84 @@ func TestContextSetGet(t *testing.T) {
 		t.Errorf("Expected body 'value', got '%s'", w.Body.String())
 	}
 }
+
+func TestContextImplementsContext(t *testing.T) {
+	app := vayu.New()
+
+	type ctxKey struct{}
+
+	var (
+		valueFromCtx   any
+		valueFromStore any
+		hasDeadline    bool
+		requestSynced  bool
+	)
+	app.GET("/ctx", func(c *vayu.Context, next vayu.NextFunc) {
+		// *vayu.Context can be passed anywhere a context.Context is expected
+		var ctx context.Context = c
+
+		c.SetContext(context.WithValue(c.Ctx, ctxKey{}, "from-ctx"))
+		c.Set("user", "alice")
+
+		valueFromCtx = ctx.Value(ctxKey{})
+		valueFromStore = ctx.Value("user")
+		_, hasDeadline = ctx.Deadline()
+		requestSynced = c.Request.Context().Value(ctxKey{}) == "from-ctx"
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/ctx", nil))
+
+	if valueFromCtx != "from-ctx" {
+		t.Errorf("Expected Value to delegate to Ctx, got %v", valueFromCtx)
+	}
+	if valueFromStore != "alice" {
+		t.Errorf("Expected string keys to read the store, got %v", valueFromStore)
+	}
+	if !hasDeadline {
+		t.Error("Expected the request deadline to be visible through Deadline")
+	}
+	if !requestSynced {
+		t.Error("Expected SetContext to update the request context")
+	}
+}
+
+func TestContextWithTimeoutUpdatesRequest(t *testing.T) {
+	app := vayu.New()
+	app.RequestTimeout = 0
+
+	var requestDeadline, ctxDeadline time.Time
+	app.GET("/timeout", func(c *vayu.Context, next vayu.NextFunc) {
+		c.WithTimeout(50 * time.Millisecond)
+		requestDeadline, _ = c.Request.Context().Deadline()
+		ctxDeadline, _ = c.Deadline()
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/timeout", nil))
+
+	if requestDeadline.IsZero() {
+		t.Fatal("Expected WithTimeout to set a deadline on the request context")
+	}
+	if !requestDeadline.Equal(ctxDeadline) {
+		t.Errorf("Expected request and context deadlines to match, got %v and %v", requestDeadline, ctxDeadline)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest("GET", "/cancel", nil).WithContext(parent)
+
+	// Without Ctx set, the request context is used
+	c := &vayu.Context{Request: req}
+	if c.Err() != nil {
+		t.Fatalf("Expected no error before cancellation, got %v", c.Err())
+	}
+
+	cancel()
+	<-c.Done()
+
+	if !errors.Is(c.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", c.Err())
+	}
+}
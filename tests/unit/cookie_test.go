@@ -0,0 +1,210 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	oldSecret = bytes.Repeat([]byte("o"), 32)
+	newSecret = bytes.Repeat([]byte("n"), 32)
+)
+
+// responseCookie returns the named cookie set by a response.
+func responseCookie(resp *httptest.ResponseRecorder, name string) *http.Cookie {
+	for _, cookie := range resp.Result().Cookies() {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestCookieHelpers(t *testing.T) {
+	app := vayu.New()
+	app.GET("/set", func(c *vayu.Context, next vayu.NextFunc) {
+		c.SetCookie(vayu.NewCookie("theme", "dark"))
+		c.SetCookie(&http.Cookie{Name: "plain", Value: "1"})
+		c.ClearCookie(&http.Cookie{Name: "old", Path: "/account", Domain: "example.com"})
+	})
+	app.GET("/get", func(c *vayu.Context, next vayu.NextFunc) {
+		theme, err := c.Cookie("theme")
+		assert.NoError(t, err)
+		_, err = c.Cookie("missing")
+		assert.ErrorIs(t, err, http.ErrNoCookie)
+		c.Send(200, theme)
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/set", nil))
+
+	theme := responseCookie(resp, "theme")
+	if assert.NotNil(t, theme) {
+		assert.Equal(t, "dark", theme.Value)
+		assert.Equal(t, "/", theme.Path)
+		assert.True(t, theme.HttpOnly)
+		assert.True(t, theme.Secure)
+		assert.Equal(t, http.SameSiteLaxMode, theme.SameSite)
+	}
+	if plain := responseCookie(resp, "plain"); assert.NotNil(t, plain) {
+		assert.Equal(t, "/", plain.Path)
+		assert.Equal(t, http.SameSiteLaxMode, plain.SameSite)
+		assert.False(t, plain.HttpOnly)
+	}
+	if old := responseCookie(resp, "old"); assert.NotNil(t, old) {
+		assert.Equal(t, -1, old.MaxAge)
+		assert.Equal(t, "/account", old.Path)
+		assert.Equal(t, "example.com", old.Domain)
+	}
+
+	req := httptest.NewRequest("GET", "/get", nil)
+	req.AddCookie(&http.Cookie{Name: "theme", Value: "dark"})
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+	assert.Equal(t, "dark", resp.Body.String())
+}
+
+func secureCookieApp(t *testing.T, keys *vayu.CookieKeys) (*vayu.App, *string, *error) {
+	t.Helper()
+	var value string
+	var readErr error
+
+	app := vayu.New().SetCookieKeys(keys)
+	app.GET("/set", func(c *vayu.Context, next vayu.NextFunc) {
+		assert.NoError(t, c.SetSignedCookie(vayu.NewCookie("user", "ada; admin=true")))
+		assert.NoError(t, c.SetEncryptedCookie(vayu.NewCookie("secret", "launch codes")))
+	})
+	app.GET("/signed", func(c *vayu.Context, next vayu.NextFunc) {
+		value, readErr = c.SignedCookie("user")
+	})
+	app.GET("/encrypted", func(c *vayu.Context, next vayu.NextFunc) {
+		value, readErr = c.EncryptedCookie("secret")
+	})
+	return app, &value, &readErr
+}
+
+func TestSignedAndEncryptedCookies(t *testing.T) {
+	app, value, readErr := secureCookieApp(t, vayu.MustNewCookieKeys(newSecret))
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/set", nil))
+	signed := responseCookie(resp, "user")
+	encrypted := responseCookie(resp, "secret")
+	if !assert.NotNil(t, signed) || !assert.NotNil(t, encrypted) {
+		return
+	}
+	assert.NotContains(t, encrypted.Value, "launch")
+
+	read := func(path string, cookie *http.Cookie) {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(cookie)
+		app.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	read("/signed", signed)
+	assert.NoError(t, *readErr)
+	assert.Equal(t, "ada; admin=true", *value)
+
+	read("/encrypted", encrypted)
+	assert.NoError(t, *readErr)
+	assert.Equal(t, "launch codes", *value)
+
+	// Tampering is detected
+	forged := *signed
+	forged.Value = strings.Replace(signed.Value, signed.Value[:4], "AAAA", 1)
+	read("/signed", &forged)
+	assert.ErrorIs(t, *readErr, vayu.ErrInvalidCookie)
+
+	forged = *encrypted
+	forged.Value = encrypted.Value[:len(encrypted.Value)-2] + "AA"
+	read("/encrypted", &forged)
+	assert.ErrorIs(t, *readErr, vayu.ErrInvalidCookie)
+
+	// Values are bound to the cookie name
+	moved := &http.Cookie{Name: "secret", Value: signed.Value}
+	read("/encrypted", moved)
+	assert.ErrorIs(t, *readErr, vayu.ErrInvalidCookie)
+}
+
+func TestSecureCookieDefaults(t *testing.T) {
+	app := vayu.New().SetCookieKeys(vayu.MustNewCookieKeys(newSecret))
+	app.GET("/set", func(c *vayu.Context, next vayu.NextFunc) {
+		assert.NoError(t, c.SetSignedCookie(&http.Cookie{Name: "user", Value: "ada"}))
+		assert.NoError(t, c.SetEncryptedCookie(&http.Cookie{
+			Name:     "prefs",
+			Value:    "beta",
+			Path:     "/account",
+			MaxAge:   3600,
+			SameSite: http.SameSiteStrictMode,
+		}))
+		// Plain-HTTP development: HttpOnly alone leaves Secure off
+		assert.NoError(t, c.SetSignedCookie(&http.Cookie{Name: "dev", Value: "1", HttpOnly: true}))
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/set", nil))
+
+	if user := responseCookie(resp, "user"); assert.NotNil(t, user) {
+		assert.Equal(t, "/", user.Path)
+		assert.True(t, user.HttpOnly)
+		assert.True(t, user.Secure)
+		assert.Equal(t, http.SameSiteLaxMode, user.SameSite)
+	}
+	if prefs := responseCookie(resp, "prefs"); assert.NotNil(t, prefs) {
+		assert.Equal(t, "/account", prefs.Path)
+		assert.Equal(t, 3600, prefs.MaxAge)
+		assert.True(t, prefs.HttpOnly)
+		assert.True(t, prefs.Secure)
+		assert.Equal(t, http.SameSiteStrictMode, prefs.SameSite)
+	}
+	if dev := responseCookie(resp, "dev"); assert.NotNil(t, dev) {
+		assert.True(t, dev.HttpOnly)
+		assert.False(t, dev.Secure)
+	}
+}
+
+func TestCookieKeyRotation(t *testing.T) {
+	oldApp, _, _ := secureCookieApp(t, vayu.MustNewCookieKeys(oldSecret))
+	resp := httptest.NewRecorder()
+	oldApp.ServeHTTP(resp, httptest.NewRequest("GET", "/set", nil))
+	signed, encrypted := responseCookie(resp, "user"), responseCookie(resp, "secret")
+
+	// The rotated app still reads cookies made with the old secret
+	app, value, readErr := secureCookieApp(t, vayu.MustNewCookieKeys(newSecret, oldSecret))
+	for path, cookie := range map[string]*http.Cookie{"/signed": signed, "/encrypted": encrypted} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.AddCookie(cookie)
+		app.ServeHTTP(httptest.NewRecorder(), req)
+		assert.NoError(t, *readErr, path)
+		assert.NotEmpty(t, *value, path)
+	}
+
+	// Once the old secret is removed they are rejected
+	app, _, readErr = secureCookieApp(t, vayu.MustNewCookieKeys(newSecret))
+	req := httptest.NewRequest("GET", "/signed", nil)
+	req.AddCookie(signed)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.ErrorIs(t, *readErr, vayu.ErrInvalidCookie)
+}
+
+func TestCookieKeysErrors(t *testing.T) {
+	_, err := vayu.NewCookieKeys()
+	assert.Error(t, err)
+	_, err = vayu.NewCookieKeys([]byte("short"))
+	assert.Error(t, err)
+
+	app := vayu.New()
+	var setErr error
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		setErr = c.SetSignedCookie(vayu.NewCookie("a", "b"))
+	})
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, errors.Is(setErr, vayu.ErrNoCookieKeys))
+}
@@ -0,0 +1,175 @@
+package unit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeReport creates a text file with known content and modification time.
+func writeReport(t *testing.T) (string, time.Time) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "report.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("0123456789"), 0o644))
+	modtime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.NoError(t, os.Chtimes(path, modtime, modtime))
+	return path, modtime
+}
+
+// fileApp serves handler for GET and HEAD requests to /file.
+func fileApp(handler func(c *vayu.Context) error) *vayu.App {
+	app := vayu.New()
+	h := func(c *vayu.Context, next vayu.NextFunc) {
+		if err := handler(c); err != nil {
+			vayu.DefaultErrorHandler(c, err)
+		}
+	}
+	app.GET("/file", h)
+	app.HEAD("/file", h)
+	return app
+}
+
+func fetch(app *vayu.App, method string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/file", nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w
+}
+
+func TestFile(t *testing.T) {
+	path, modtime := writeReport(t)
+	app := fileApp(func(c *vayu.Context) error { return c.File(path) })
+
+	w := fetch(app, "GET", nil)
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Equal(t, "0123456789", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, modtime.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+	assert.Equal(t, "bytes", w.Header().Get("Accept-Ranges"))
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-z]+-a"$`, etag)
+
+	w = fetch(app, "HEAD", nil)
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Equal(t, "10", w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Body.String())
+
+	w = fetch(app, "GET", map[string]string{"If-None-Match": etag})
+	assert.Equal(t, vayu.StatusNotModified, w.Code)
+
+	w = fetch(app, "GET", map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)})
+	assert.Equal(t, vayu.StatusNotModified, w.Code)
+}
+
+func TestFileRanges(t *testing.T) {
+	path, modtime := writeReport(t)
+	app := fileApp(func(c *vayu.Context) error { return c.File(path) })
+
+	w := fetch(app, "GET", map[string]string{"Range": "bytes=2-4"})
+	assert.Equal(t, vayu.StatusPartialContent, w.Code)
+	assert.Equal(t, "234", w.Body.String())
+	assert.Equal(t, "bytes 2-4/10", w.Header().Get("Content-Range"))
+	etag := w.Header().Get("ETag")
+
+	// If-Range with the current validator keeps the range
+	w = fetch(app, "GET", map[string]string{"Range": "bytes=-3", "If-Range": etag})
+	assert.Equal(t, "789", w.Body.String())
+	w = fetch(app, "GET", map[string]string{"Range": "bytes=-3", "If-Range": modtime.Format(http.TimeFormat)})
+	assert.Equal(t, "789", w.Body.String())
+
+	// A stale validator sends the whole file
+	w = fetch(app, "GET", map[string]string{"Range": "bytes=-3", "If-Range": `"stale"`})
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Equal(t, "0123456789", w.Body.String())
+
+	w = fetch(app, "GET", map[string]string{"Range": "bytes=50-"})
+	assert.Equal(t, vayu.StatusRequestedRangeNotSatisfiable, w.Code)
+}
+
+func TestFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, path := range []string{filepath.Join(dir, "missing.txt"), dir} {
+		var fileErr error
+		app := fileApp(func(c *vayu.Context) error {
+			fileErr = c.Attachment(path, "")
+			return fileErr
+		})
+		w := fetch(app, "GET", nil)
+		assert.Equal(t, vayu.StatusNotFound, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Disposition"))
+		assert.Empty(t, w.Header().Get("ETag"))
+
+		var httpErr *vayu.HTTPError
+		if assert.True(t, errors.As(fileErr, &httpErr)) {
+			assert.Equal(t, vayu.StatusNotFound, httpErr.Code)
+		}
+	}
+}
+
+func TestAttachmentAndInline(t *testing.T) {
+	path, _ := writeReport(t)
+
+	cases := []struct {
+		serve    func(c *vayu.Context) error
+		expected string
+	}{
+		{func(c *vayu.Context) error { return c.Attachment(path, "") }, `attachment; filename="report.txt"`},
+		{func(c *vayu.Context) error { return c.Inline(path, "q1.txt") }, `inline; filename="q1.txt"`},
+		{func(c *vayu.Context) error { return c.Attachment(path, `say "hi".txt`) }, `attachment; filename="say \"hi\".txt"`},
+		{
+			func(c *vayu.Context) error { return c.Attachment(path, "résumé 2026.txt") },
+			`attachment; filename="r_sum_ 2026.txt"; filename*=UTF-8''r%C3%A9sum%C3%A9%202026.txt`,
+		},
+	}
+	for _, tc := range cases {
+		w := fetch(fileApp(tc.serve), "GET", map[string]string{"Range": "bytes=0-0"})
+		assert.Equal(t, tc.expected, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "0", w.Body.String())
+	}
+}
+
+func TestStream(t *testing.T) {
+	modtime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	app := fileApp(func(c *vayu.Context) error {
+		c.Writer.Header().Set("Last-Modified", modtime.Format(http.TimeFormat))
+		c.Writer.Header().Set("ETag", `"v1"`)
+		return c.Stream("application/octet-stream", bytes.NewReader([]byte("abcdef")))
+	})
+
+	w := fetch(app, "GET", map[string]string{"Range": "bytes=1-2"})
+	assert.Equal(t, vayu.StatusPartialContent, w.Code)
+	assert.Equal(t, "bc", w.Body.String())
+	assert.Equal(t, "application/octet-stream", w.Header().Get("Content-Type"))
+
+	w = fetch(app, "GET", map[string]string{"If-None-Match": `"v1"`})
+	assert.Equal(t, vayu.StatusNotModified, w.Code)
+
+	w = fetch(app, "HEAD", nil)
+	assert.Equal(t, "6", w.Header().Get("Content-Length"))
+	assert.Empty(t, w.Body.String())
+
+	// Readers that cannot seek are copied whole
+	app = fileApp(func(c *vayu.Context) error {
+		return c.Stream("text/plain", struct{ io.Reader }{strings.NewReader("plain")})
+	})
+	w = fetch(app, "GET", map[string]string{"Range": "bytes=1-2"})
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Equal(t, "plain", w.Body.String())
+
+	w = fetch(app, "HEAD", nil)
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindHeaders(t *testing.T) {
+	app := vayu.New()
+
+	type RequestMeta struct {
+		Tenant   string   `header:"X-Tenant-ID" required:"true"`
+		Retries  int      `header:"x-retry-count"`
+		Accept   []string `header:"Accept-Language"`
+		Debug    bool     `header:"X-Debug"`
+		Untagged string
+		Skip     string `header:"-"`
+	}
+
+	app.GET("/meta", func(c *vayu.Context, next vayu.NextFunc) {
+		meta, err := vayu.BindHeaders[RequestMeta](c)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "acme", meta.Tenant)
+			assert.Equal(t, 3, meta.Retries)
+			assert.Equal(t, []string{"en", "fr", "de"}, meta.Accept)
+			assert.Equal(t, false, meta.Debug)
+			assert.Empty(t, meta.Skip)
+			c.Writer.WriteHeader(200)
+		} else {
+			c.Writer.WriteHeader(400)
+		}
+	})
+
+	req := httptest.NewRequest("GET", "/meta", nil)
+	req.Header.Set("X-Tenant-ID", "acme")
+	req.Header.Set("X-Retry-Count", "3")
+	// Repeated and comma-separated values are both collected into the slice
+	req.Header.Add("Accept-Language", "en, fr")
+	req.Header.Add("Accept-Language", "de")
+	req.Header.Set("-", "zz")
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+	assert.Equal(t, 200, resp.Code)
+}
+
+func TestBindHeadersErrors(t *testing.T) {
+	app := vayu.New()
+
+	type RequestMeta struct {
+		Tenant  string `header:"X-Tenant-ID" required:"true"`
+		Retries int    `header:"X-Retry-Count"`
+	}
+
+	var bindErr error
+	app.GET("/meta", func(c *vayu.Context, next vayu.NextFunc) {
+		_, bindErr = vayu.BindHeaders[RequestMeta](c)
+	})
+
+	req := httptest.NewRequest("GET", "/meta", nil)
+	req.Header.Set("X-Retry-Count", "many")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if assert.Error(t, bindErr) {
+		assert.Contains(t, bindErr.Error(), "required header X-Tenant-ID missing")
+		assert.Contains(t, bindErr.Error(), "header X-Retry-Count: cannot convert 'many' to int")
+	}
+}
+
+func TestBindCookies(t *testing.T) {
+	app := vayu.New()
+
+	type SessionCookies struct {
+		Session string `cookie:"session" required:"true"`
+		Theme   string `cookie:"theme"`
+		Visits  int    `cookie:"visits"`
+	}
+
+	app.GET("/session", func(c *vayu.Context, next vayu.NextFunc) {
+		cookies, err := vayu.BindCookies[SessionCookies](c)
+		if err != nil {
+			c.BadRequest(err.Error())
+			return
+		}
+		c.OK(cookies)
+	})
+
+	req := httptest.NewRequest("GET", "/session", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "abc123"})
+	req.AddCookie(&http.Cookie{Name: "visits", Value: "7"})
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	assert.Equal(t, 200, resp.Code)
+	assert.JSONEq(t, `{"Session":"abc123","Theme":"","Visits":7}`, resp.Body.String())
+
+	// Missing required cookie
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/session", nil))
+	assert.Equal(t, 400, resp.Code)
+	assert.Contains(t, resp.Body.String(), "required cookie session missing")
+}
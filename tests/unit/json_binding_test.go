@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type jsonItem struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Extra any    `json:"extra"`
+}
+
+// postJSON runs a POST /items request whose handler binds a jsonItem.
+func postJSON(app *vayu.App, body string, chunked bool) (*httptest.ResponseRecorder, error) {
+	var bindErr error
+	app.POST("/items", func(c *vayu.Context, next vayu.NextFunc) {
+		var item jsonItem
+		if bindErr = c.BindJSON(&item); bindErr != nil {
+			code := vayu.StatusBadRequest
+			var sc vayu.StatusCoder
+			if errors.As(bindErr, &sc) {
+				code = sc.StatusCode()
+			}
+			c.JSON(code, map[string]string{"error": bindErr.Error()})
+			return
+		}
+		c.OK(item)
+	})
+
+	var reader io.Reader = strings.NewReader(body)
+	if chunked {
+		// Hide the length so the limit is enforced while reading
+		reader = io.MultiReader(reader)
+	}
+	req := httptest.NewRequest("POST", "/items", reader)
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+	return resp, bindErr
+}
+
+func TestJSONBindingMaxBodyBytes(t *testing.T) {
+	app := vayu.New()
+	app.SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 16})
+
+	resp, _ := postJSON(app, `{"name":"a"}`, false)
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+
+	resp, err := postJSON(vayu.New().SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 16}), `{"name":"a very long name"}`, false)
+	assert.Equal(t, vayu.StatusRequestEntityTooLarge, resp.Code)
+	assert.EqualError(t, err, "request body too large: limit is 16 bytes")
+
+	resp, _ = postJSON(vayu.New().SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 16}), `{"name":"a very long name"}`, true)
+	assert.Equal(t, vayu.StatusRequestEntityTooLarge, resp.Code)
+
+	// A negative limit disables the check
+	resp, _ = postJSON(vayu.New().SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: -1}), `{"name":"`+strings.Repeat("x", 11<<20)+`"}`, true)
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+
+	// The default limit applies otherwise
+	resp, _ = postJSON(vayu.New(), `{"name":"`+strings.Repeat("x", 11<<20)+`"}`, true)
+	assert.Equal(t, vayu.StatusRequestEntityTooLarge, resp.Code)
+}
+
+func TestJSONBindingDisallowUnknownFields(t *testing.T) {
+	body := `{"name":"a","colour":"red"}`
+
+	resp, _ := postJSON(vayu.New(), body, false)
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+
+	app := vayu.New().SetJSONBinding(vayu.JSONBindingConfig{DisallowUnknownFields: true})
+	resp, err := postJSON(app, body, false)
+	assert.Equal(t, vayu.StatusBadRequest, resp.Code)
+
+	var be *vayu.BindingError
+	if assert.True(t, errors.As(err, &be)) {
+		assert.Equal(t, "colour", be.Errors[0].Field)
+	}
+	assert.Contains(t, err.Error(), "JSON field colour: unknown field")
+}
+
+func TestJSONBindingUseNumber(t *testing.T) {
+	var extra any
+	app := vayu.New().SetJSONBinding(vayu.JSONBindingConfig{UseNumber: true})
+	app.POST("/items", func(c *vayu.Context, next vayu.NextFunc) {
+		item, _ := vayu.BindJSONBody[jsonItem](c)
+		extra = item.Extra
+	})
+
+	req := httptest.NewRequest("POST", "/items", strings.NewReader(`{"extra":12345678901234567890}`))
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, json.Number("12345678901234567890"), extra)
+}
+
+func TestJSONBindingTrailingData(t *testing.T) {
+	body := `{"name":"a"} {"name":"b"}`
+
+	resp, _ := postJSON(vayu.New(), body, false)
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+
+	app := vayu.New().SetJSONBinding(vayu.JSONBindingConfig{DisallowTrailingData: true})
+	resp, err := postJSON(app, body, false)
+	assert.Equal(t, vayu.StatusBadRequest, resp.Code)
+	assert.EqualError(t, err, "json body: unexpected data after top-level value (at byte 12)")
+
+	// Trailing whitespace is fine
+	resp, _ = postJSON(vayu.New().SetJSONBinding(vayu.JSONBindingConfig{DisallowTrailingData: true}), "{\"name\":\"a\"}\n", false)
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+}
+
+func TestJSONBindingErrorOffsets(t *testing.T) {
+	_, err := postJSON(vayu.New(), `{"name":"a","count":"three"}`, false)
+	var be *vayu.BindingError
+	if assert.True(t, errors.As(err, &be)) {
+		assert.Equal(t, "count", be.Errors[0].Field)
+		assert.Equal(t, int64(len(`{"name":"a","count":"three"`)), be.Errors[0].Offset)
+	}
+
+	_, err = postJSON(vayu.New(), `{"name": x}`, false)
+	assert.EqualError(t, err, "json body: invalid character 'x' looking for beginning of value (at byte 10)")
+}
+
+func TestJSONBindingRouteOverride(t *testing.T) {
+	app := vayu.New().SetJSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 8})
+
+	var bindErr error
+	handler := func(c *vayu.Context, next vayu.NextFunc) {
+		_, bindErr = vayu.BindBody[jsonItem](c)
+	}
+	app.POST("/small", handler)
+	app.POST("/large", vayu.WithMiddleware(handler, vayu.JSONBinding(vayu.JSONBindingConfig{MaxBodyBytes: 1024})))
+
+	body := `{"name":"route level"}`
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/small", strings.NewReader(body)))
+	var sc vayu.StatusCoder
+	if assert.True(t, errors.As(bindErr, &sc)) {
+		assert.Equal(t, vayu.StatusRequestEntityTooLarge, sc.StatusCode())
+	}
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/large", strings.NewReader(body)))
+	assert.NoError(t, bindErr)
+}
@@ -2,91 +2,130 @@ package unit
 
 import (
 	"bytes"
-	"log"
-	"os"
+	"context"
+	"log/slog"
+	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/kaushiksamanta/vayu"
-	"net/http/httptest"
 )
 
-// TestSilentMode tests the SilentMode functionality
-func TestSilentMode(t *testing.T) {
-	// Save original SilentMode value and restore it after the test
-	originalSilentMode := vayu.SilentMode
-	defer func() { vayu.SilentMode = originalSilentMode }()
-
-	// First test with SilentMode explicitly disabled (verbose logs)
-	t.Run("VerboseLogs", func(t *testing.T) {
-		// Capture log output
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
-		defer log.SetOutput(os.Stderr) // restore default output
-
-		// Set SilentMode to false
-		vayu.SilentMode = "false"
-
-		// Run test that will trigger a panic
-		app := vayu.New()
-		app.Use(vayu.ErrorHandlerMiddleware(nil)) // Use default error handler
-		app.GET("/panic", func(c *vayu.Context, next vayu.NextFunc) {
-			panic("test panic with verbose logs")
-		})
-
-		req := httptest.NewRequest("GET", "/panic", nil)
-		w := httptest.NewRecorder()
-		app.ServeHTTP(w, req)
-
-		// Check that panic was recovered (response code should be 500)
-		if w.Code != vayu.StatusInternalServerError {
-			t.Fatalf("Expected status %d, got %d", vayu.StatusInternalServerError, w.Code)
-		}
+// newPanicApp creates an app whose only route panics
+func newPanicApp(logger *slog.Logger) *vayu.App {
+	app := vayu.New()
+	app.SetLogger(logger)
+	app.Use(vayu.ErrorHandlerMiddleware(nil)) // Use default error handler
+	app.GET("/panic/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		panic("test panic")
+	})
+	return app
+}
 
-		// Check that panic logs appear when SilentMode is false
-		logOutput := logBuffer.String()
-		if !strings.Contains(logOutput, "Panic recovered:") {
-			t.Errorf("Expected panic logs with SilentMode=false, but found none")
-			t.Logf("Log output was: %s", logOutput)
+// TestPanicLogging tests that panics are logged through the app logger
+func TestPanicLogging(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	app := newPanicApp(logger)
+
+	req := httptest.NewRequest("GET", "/panic/42", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	// Check that panic was recovered (response code should be 500)
+	if w.Code != vayu.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", vayu.StatusInternalServerError, w.Code)
+	}
+
+	logOutput := logBuffer.String()
+	for _, want := range []string{
+		`msg="panic recovered"`,
+		`msg="unhandled error"`,
+		"request_id=req-1",
+		"method=GET",
+		"route=/panic/:id",
+		"params.id=42",
+	} {
+		if !strings.Contains(logOutput, want) {
+			t.Errorf("Expected log output to contain %q", want)
 		}
+	}
+	if t.Failed() {
+		t.Logf("Log output was: %s", logOutput)
+	}
+}
+
+// TestClientErrorPanicLogging tests that a 4xx panic from a Must helper is
+// logged as a warning without an ERROR record or stack
+func TestClientErrorPanicLogging(t *testing.T) {
+	var logBuffer bytes.Buffer
+	app := vayu.New()
+	app.SetLogger(slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.GET("/u/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		c.OK(map[string]int{"id": vayu.MustPathParam[int](c, "id")})
 	})
 
-	// Now test with SilentMode explicitly enabled (silent logs)
-	t.Run("SilentLogs", func(t *testing.T) {
-		// Capture log output
-		var logBuffer bytes.Buffer
-		log.SetOutput(&logBuffer)
-		defer log.SetOutput(os.Stderr) // restore default output
-
-		// Set SilentMode to true
-		vayu.SilentMode = "true"
-
-		// Run test that will trigger a panic
-		app := vayu.New()
-		app.Use(vayu.ErrorHandlerMiddleware(nil)) // Use default error handler
-		app.GET("/panic", func(c *vayu.Context, next vayu.NextFunc) {
-			panic("test panic with silent logs")
-		})
-
-		req := httptest.NewRequest("GET", "/panic", nil)
-		w := httptest.NewRecorder()
-		app.ServeHTTP(w, req)
-
-		// Check that panic was recovered (response code should be 500)
-		if w.Code != vayu.StatusInternalServerError {
-			t.Fatalf("Expected status %d, got %d", vayu.StatusInternalServerError, w.Code)
-		}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/u/abc", nil))
+
+	if w.Code != vayu.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", vayu.StatusBadRequest, w.Code)
+	}
+	logOutput := logBuffer.String()
+	if strings.Contains(logOutput, "level=ERROR") || strings.Contains(logOutput, "stack=") {
+		t.Errorf("Expected no ERROR record or stack, got: %s", logOutput)
+	}
+	if !strings.Contains(logOutput, `level=WARN msg="request error"`) {
+		t.Errorf("Expected a request error warning, got: %s", logOutput)
+	}
+}
 
-		// Check that panic logs do NOT appear when SilentMode is true
-		logOutput := logBuffer.String()
-		if strings.Contains(logOutput, "Panic recovered:") {
-			t.Errorf("Expected NO panic logs with SilentMode=true, but found some")
-			t.Logf("Log output was: %s", logOutput)
-		}
+// TestSilentLogLevel tests that a logger at a silent level discards panic logs
+func TestSilentLogLevel(t *testing.T) {
+	var logBuffer bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logBuffer, &slog.HandlerOptions{Level: vayu.LevelSilent}))
+	app := newPanicApp(logger)
 
-		// We should still see the regular error log
-		if !strings.Contains(logOutput, "Error:") {
-			t.Logf("Note: Regular error logs are still shown: %s", logOutput)
-		}
-	})
+	req := httptest.NewRequest("GET", "/panic/1", nil)
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if w.Code != vayu.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", vayu.StatusInternalServerError, w.Code)
+	}
+
+	if logBuffer.Len() != 0 {
+		t.Errorf("Expected no log output with LevelSilent, got: %s", logBuffer.String())
+	}
+}
+
+// TestDefaultLogLevel tests the LogLevel variable and SetLogLevel
+func TestDefaultLogLevel(t *testing.T) {
+	originalLogLevel := vayu.LogLevel
+	defer func() { vayu.LogLevel = originalLogLevel }()
+
+	ctx := context.Background()
+
+	// Tests run silently unless a level is requested
+	vayu.LogLevel = ""
+	app := vayu.New()
+	if app.Logger().Enabled(ctx, slog.LevelError) {
+		t.Error("Expected default logger to be silent under go test")
+	}
+
+	vayu.LogLevel = "warn"
+	app = vayu.New()
+	if app.Logger().Enabled(ctx, slog.LevelInfo) {
+		t.Error("Expected info to be disabled at warn level")
+	}
+	if !app.Logger().Enabled(ctx, slog.LevelWarn) {
+		t.Error("Expected warn to be enabled at warn level")
+	}
+
+	app.SetLogLevel(slog.LevelDebug)
+	if !app.Logger().Enabled(ctx, slog.LevelDebug) {
+		t.Error("Expected debug to be enabled after SetLogLevel")
+	}
 }
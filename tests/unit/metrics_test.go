@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	app := vayu.New()
+	app.Metrics("/metrics", vayu.MetricsConfig{Buckets: []float64{0.1, 1}})
+
+	app.GET("/users/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		if c.Params["id"] == "0" {
+			c.NotFound("no such user")
+			return
+		}
+		c.Send(vayu.StatusOK, "user")
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/3", "/users/0"} {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if w.Code != vayu.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", vayu.StatusOK, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != vayu.MetricsContentType {
+		t.Errorf("Expected Content-Type '%s', got '%s'", vayu.MetricsContentType, ct)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE vayu_http_requests_total counter",
+		`vayu_http_requests_total{method="GET",route="/users/:id",status="200"} 3`,
+		`vayu_http_requests_total{method="GET",route="/users/:id",status="404"} 1`,
+		"# TYPE vayu_http_request_duration_seconds histogram",
+		`vayu_http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="0.1"} 3`,
+		`vayu_http_request_duration_seconds_bucket{method="GET",route="/users/:id",status="200",le="+Inf"} 3`,
+		`vayu_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 3`,
+		"# TYPE vayu_http_requests_in_flight gauge",
+		`vayu_http_requests_in_flight{method="GET",route="/users/:id"} 0`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics output to contain %q", want)
+		}
+	}
+
+	// Raw paths and the metrics endpoint itself must not become labels
+	if strings.Contains(body, "/users/1") || strings.Contains(body, `route="/metrics"`) {
+		t.Errorf("Unexpected label values in metrics output:\n%s", body)
+	}
+}
+
+func TestMetricsInFlight(t *testing.T) {
+	m := vayu.NewMetrics(vayu.MetricsConfig{Namespace: "api"})
+	app := vayu.New()
+	app.Use(m.Middleware())
+
+	var snapshot string
+	app.POST("/jobs", func(c *vayu.Context, next vayu.NextFunc) {
+		var sb strings.Builder
+		m.WriteTo(&sb)
+		snapshot = sb.String()
+		c.Send(vayu.StatusAccepted, "queued")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/jobs", nil))
+
+	if !strings.Contains(snapshot, `api_http_requests_in_flight{method="POST",route="/jobs"} 1`) {
+		t.Errorf("Expected one in-flight request while handling, got:\n%s", snapshot)
+	}
+
+	var sb strings.Builder
+	m.WriteTo(&sb)
+	if !strings.Contains(sb.String(), `api_http_requests_total{method="POST",route="/jobs",status="202"} 1`) {
+		t.Errorf("Expected completed request to be counted, got:\n%s", sb.String())
+	}
+}
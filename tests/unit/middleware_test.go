@@ -3,6 +3,7 @@ package unit
 import (
 	"github.com/kaushiksamanta/vayu"
 	"net/http/httptest"
+	"slices"
 	"testing"
 )
 
@@ -159,3 +160,61 @@ func TestErrorHandlerMiddleware(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", vayu.StatusInternalServerError, w.Code)
 	}
 }
+
+func TestWithMiddlewareSubChains(t *testing.T) {
+	var order []string
+	record := func(name string) vayu.HandlerFunc {
+		return func(c *vayu.Context, next vayu.NextFunc) {
+			order = append(order, name)
+			next()
+			order = append(order, "after_"+name)
+		}
+	}
+
+	app := vayu.New()
+	app.Use(record("global"))
+	app.GET("/nested", vayu.WithMiddleware(
+		vayu.WithMiddleware(func(c *vayu.Context, next vayu.NextFunc) {
+			order = append(order, "handler")
+			next()
+		}, record("inner")),
+		record("outer"),
+	))
+	app.GET("/stop", vayu.WithMiddleware(func(c *vayu.Context, next vayu.NextFunc) {
+		order = append(order, "handler")
+	}, record("route")))
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/nested", nil))
+	want := []string{"global", "outer", "inner", "handler", "after_inner", "after_outer", "after_global"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+
+	order = nil
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/stop", nil))
+	want = []string{"global", "route", "handler", "after_route", "after_global"}
+	if !slices.Equal(order, want) {
+		t.Errorf("Expected order %v, got %v", want, order)
+	}
+}
+
+func TestWithMiddlewareAllocations(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocation counts vary under the race detector")
+	}
+	pass := func(c *vayu.Context, next vayu.NextFunc) { next() }
+	handler := func(c *vayu.Context, next vayu.NextFunc) {}
+
+	app := vayu.New()
+	app.GET("/plain", handler)
+	app.GET("/wrapped", vayu.WithMiddleware(handler, pass, pass))
+
+	allocs := func(path string) float64 {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		return testing.AllocsPerRun(100, func() { app.ServeHTTP(w, req) })
+	}
+	if plain, wrapped := allocs("/plain"), allocs("/wrapped"); wrapped > plain {
+		t.Errorf("Expected WithMiddleware to add no allocations, got %v vs %v", wrapped, plain)
+	}
+}
@@ -0,0 +1,161 @@
+package unit
+
+import (
+	"errors"
+	"html/template"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type negotiatedUser struct {
+	ID   int    `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+// negotiate serves data through Negotiate for a request with the given
+// Accept header and returns the recorder and the error Negotiate returned.
+func negotiate(t *testing.T, accept string, data any, offers ...vayu.Offer) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	app := vayu.New()
+	var negotiateErr error
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		negotiateErr = c.Negotiate(vayu.StatusOK, data, offers...)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w, negotiateErr
+}
+
+func TestNegotiateDefaultsToJSON(t *testing.T) {
+	w, err := negotiate(t, "", negotiatedUser{1, "Ada"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"id":1,"name":"Ada"}`, w.Body.String())
+	assert.Equal(t, "Accept", w.Header().Get("Vary"))
+
+	w, _ = negotiate(t, "*/*", negotiatedUser{1, "Ada"})
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestNegotiateQValues(t *testing.T) {
+	w, err := negotiate(t, "application/json;q=0.5, application/xml", negotiatedUser{1, "Ada"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "<negotiatedUser><id>1</id><name>Ada</name></negotiatedUser>")
+
+	// The most specific range decides: text/plain is excluded despite text/*
+	w, _ = negotiate(t, "text/*, text/plain;q=0", "hello")
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+
+	w, _ = negotiate(t, "text/plain", 42)
+	assert.Equal(t, "text/plain", w.Header().Get("Content-Type"))
+	assert.Equal(t, "42", w.Body.String())
+}
+
+func TestNegotiateSkipsRenderersThatCannotRenderData(t *testing.T) {
+	// A browser prefers HTML, but a struct has no HTML form without a template
+	accept := "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8"
+	w, err := negotiate(t, accept, negotiatedUser{1, "Ada"})
+	assert.NoError(t, err)
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+
+	// Maps cannot be encoded as XML either
+	w, _ = negotiate(t, accept, map[string]int{"count": 3})
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	// Plain strings are escaped as HTML
+	w, _ = negotiate(t, accept, "<b>hi</b>")
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	assert.Equal(t, "&lt;b&gt;hi&lt;/b&gt;", w.Body.String())
+}
+
+func TestNegotiateNotAcceptable(t *testing.T) {
+	w, err := negotiate(t, "image/png", negotiatedUser{1, "Ada"})
+	assert.True(t, errors.Is(err, vayu.ErrNotAcceptable))
+
+	var sc vayu.StatusCoder
+	if assert.True(t, errors.As(err, &sc)) {
+		assert.Equal(t, vayu.StatusNotAcceptable, sc.StatusCode())
+	}
+	assert.Contains(t, err.Error(), "application/json")
+	assert.Empty(t, w.Body.String())
+
+	// Nothing acceptable can render the data
+	_, err = negotiate(t, "text/plain", negotiatedUser{1, "Ada"})
+	assert.True(t, errors.Is(err, vayu.ErrNotAcceptable))
+
+	// The default error handler turns it into a 406 response
+	app := vayu.New()
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		if err := c.Negotiate(vayu.StatusOK, "hi"); err != nil {
+			vayu.DefaultErrorHandler(c, err)
+		}
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "application/pdf")
+	rec := httptest.NewRecorder()
+	app.ServeHTTP(rec, req)
+	assert.Equal(t, vayu.StatusNotAcceptable, rec.Code)
+	assert.Equal(t, "Accept", rec.Header().Get("Vary"))
+}
+
+func TestNegotiateOffers(t *testing.T) {
+	tmpl := template.Must(template.New("user").Parse(`<h1>{{.Name}}</h1>`))
+	offer := vayu.Offer{MediaType: "text/html", Renderer: vayu.TemplateRenderer(tmpl, "user")}
+
+	w, err := negotiate(t, "text/html, application/json;q=0.9", negotiatedUser{1, "<Ada>"}, offer)
+	assert.NoError(t, err)
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	assert.Equal(t, "<h1>&lt;Ada&gt;</h1>", w.Body.String())
+
+	// Offers come first among equally acceptable types
+	w, _ = negotiate(t, "", negotiatedUser{1, "Ada"}, offer)
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+
+	// Registered renderers remain available
+	w, _ = negotiate(t, "application/json", negotiatedUser{1, "Ada"}, offer)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+}
+
+func TestRegisterRenderer(t *testing.T) {
+	vayu.RegisterRenderer("text/tab-separated-values", func(c *vayu.Context, code int, data any) error {
+		user, ok := data.(negotiatedUser)
+		if !ok {
+			return vayu.ErrNotRenderable
+		}
+		c.Writer.Header().Set("Content-Type", "text/tab-separated-values")
+		c.Writer.WriteHeader(code)
+		_, err := c.Writer.Write([]byte("id\tname\n1\t" + user.Name + "\n"))
+		return err
+	})
+	defer vayu.RegisterRenderer("text/tab-separated-values", nil)
+
+	w, err := negotiate(t, "text/tab-separated-values", negotiatedUser{1, "Ada"})
+	assert.NoError(t, err)
+	assert.Equal(t, "id\tname\n1\tAda\n", w.Body.String())
+
+	vayu.RegisterRenderer("text/tab-separated-values", nil)
+	_, err = negotiate(t, "text/tab-separated-values", negotiatedUser{1, "Ada"})
+	assert.True(t, errors.Is(err, vayu.ErrNotAcceptable))
+	assert.False(t, strings.Contains(err.Error(), "text/tab-separated-values"))
+}
+
+func TestNegotiateKeepsExistingVary(t *testing.T) {
+	app := vayu.New()
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Writer.Header().Set("Vary", "Accept-Encoding, accept")
+		c.Negotiate(vayu.StatusOK, "hi")
+	})
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	assert.Equal(t, []string{"Accept-Encoding, accept"}, w.Header().Values("Vary"))
+}
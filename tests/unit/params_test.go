@@ -0,0 +1,135 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+func TestParamAccessors(t *testing.T) {
+	app := vayu.New()
+
+	var (
+		name  string
+		id    int
+		big   int64
+		uid   vayu.UUID
+		score float64
+		errs  []error
+	)
+	app.GET("/users/:name/:id/:big/:uid/:score", func(c *vayu.Context, next vayu.NextFunc) {
+		var err error
+		name = c.Param("name")
+		id, err = c.ParamInt("id")
+		errs = append(errs, err)
+		big, err = c.ParamInt64("big")
+		errs = append(errs, err)
+		uid, err = c.ParamUUID("uid")
+		errs = append(errs, err)
+		score, err = vayu.PathParam[float64](c, "score")
+		errs = append(errs, err)
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	path := "/users/alice/42/9000000000/123E4567-e89b-12d3-a456-426614174000/9.5"
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Unexpected error from accessor %d: %v", i, err)
+		}
+	}
+	if name != "alice" || id != 42 || big != 9000000000 || score != 9.5 {
+		t.Errorf("Unexpected values: name=%s id=%d big=%d score=%v", name, id, big, score)
+	}
+	if uid.String() != "123e4567-e89b-12d3-a456-426614174000" {
+		t.Errorf("Unexpected UUID %s", uid)
+	}
+}
+
+func TestParamErrors(t *testing.T) {
+	app := vayu.New()
+
+	results := map[string]error{}
+	app.GET("/items/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		_, results["int"] = c.ParamInt("id")
+		_, results["int8"] = vayu.PathParam[int8](c, "id")
+		_, results["uuid"] = c.ParamUUID("id")
+		_, results["missing"] = c.ParamInt("nope")
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items/300", nil))
+
+	if results["int"] != nil {
+		t.Errorf("Expected 300 to parse as int, got %v", results["int"])
+	}
+
+	for _, key := range []string{"int8", "uuid", "missing"} {
+		var pe *vayu.ParamError
+		if !errors.As(results[key], &pe) {
+			t.Fatalf("Expected *vayu.ParamError for %s, got %v", key, results[key])
+		}
+		if pe.StatusCode() != vayu.StatusBadRequest {
+			t.Errorf("Expected status %d for %s, got %d", vayu.StatusBadRequest, key, pe.StatusCode())
+		}
+	}
+
+	if !errors.Is(results["missing"], vayu.ErrParamMissing) {
+		t.Errorf("Expected ErrParamMissing, got %v", results["missing"])
+	}
+	if !errors.Is(results["uuid"], vayu.ErrInvalidUUID) {
+		t.Errorf("Expected ErrInvalidUUID, got %v", results["uuid"])
+	}
+	if got := results["missing"].Error(); got != "path parameter nope is missing" {
+		t.Errorf("Unexpected error message '%s'", got)
+	}
+}
+
+func TestMustPathParamBadRequest(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.GET("/orders/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		id := vayu.MustPathParam[int](c, "id")
+		c.OK(map[string]int{"id": id})
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/orders/abc", nil))
+
+	if w.Code != vayu.StatusBadRequest {
+		t.Fatalf("Expected status code %d, got %d", vayu.StatusBadRequest, w.Code)
+	}
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if body["error"] == "" {
+		t.Error("Expected a descriptive error message")
+	}
+
+	w = httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/orders/7", nil))
+	if w.Code != vayu.StatusOK {
+		t.Errorf("Expected status code %d, got %d", vayu.StatusOK, w.Code)
+	}
+}
+
+func TestHTTPErrorStatus(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.Recovery())
+	app.GET("/conflict", func(c *vayu.Context, next vayu.NextFunc) {
+		panic(vayu.NewHTTPError(vayu.StatusConflict, "already exists"))
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/conflict", nil))
+
+	if w.Code != vayu.StatusConflict {
+		t.Errorf("Expected status code %d, got %d", vayu.StatusConflict, w.Code)
+	}
+}
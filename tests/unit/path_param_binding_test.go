@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBindPathParams(t *testing.T) {
+	app := vayu.New()
+
+	type PostRoute struct {
+		User    string        `path:"user"`
+		PostID  int64         `path:"post"`
+		Draft   bool          `path:"draft"`
+		Cache   time.Duration `path:"ttl"`
+		Ignored string
+	}
+
+	app.GET("/users/:user/posts/:post/:draft/:ttl", func(c *vayu.Context, next vayu.NextFunc) {
+		params, err := vayu.BindPathParams[PostRoute](c)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "alice", params.User)
+			assert.Equal(t, int64(42), params.PostID)
+			assert.Equal(t, true, params.Draft)
+			assert.Equal(t, 5*time.Minute, params.Cache)
+			assert.Equal(t, "", params.Ignored)
+			c.Writer.WriteHeader(200)
+		} else {
+			c.Writer.WriteHeader(400)
+		}
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/users/alice/posts/42/true/5m", nil))
+	assert.Equal(t, 200, resp.Code)
+}
+
+func TestBindPathParamErrors(t *testing.T) {
+	app := vayu.New()
+
+	type ItemRoute struct {
+		ID      int    `path:"id"`
+		Version string `path:"version"`
+	}
+
+	var bindErr error
+	app.GET("/items/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		_, bindErr = vayu.BindPathParams[ItemRoute](c)
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/items/abc", nil))
+
+	if assert.Error(t, bindErr) {
+		// Both the conversion failure and the parameter missing from the route are reported
+		assert.Contains(t, bindErr.Error(), "path parameter id: cannot convert 'abc' to int")
+		assert.Contains(t, bindErr.Error(), "required path parameter version missing")
+	}
+
+	type NoTags struct {
+		ID int
+	}
+	app.GET("/untagged/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		_, bindErr = vayu.BindPathParams[NoTags](c)
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/untagged/1", nil))
+	assert.EqualError(t, bindErr, "no fields with 'path' tag found in type NoTags")
+}
+
+func TestMustBindPathParams(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+
+	type OrderRoute struct {
+		ID uint `path:"id"`
+	}
+	app.GET("/orders/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		route := vayu.MustBindPathParams[OrderRoute](c)
+		c.OK(route)
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/orders/-1", nil))
+	assert.Equal(t, vayu.StatusBadRequest, resp.Code)
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/orders/9", nil))
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+}
@@ -0,0 +1,141 @@
+package unit
+
+import (
+	"bytes"
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type resolved struct {
+	ip, scheme, host string
+}
+
+// resolve runs a request from remoteAddr with the given headers through an
+// app trusting proxies and returns what ClientIP, Scheme and Host report.
+func resolve(t *testing.T, proxies []string, remoteAddr string, headers map[string][]string) resolved {
+	t.Helper()
+	app := vayu.New()
+	assert.NoError(t, app.SetTrustedProxies(proxies...))
+
+	var got resolved
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		got = resolved{c.ClientIP(), c.Scheme(), c.Host()}
+	})
+
+	req := httptest.NewRequest("GET", "http://app.internal/", nil)
+	req.RemoteAddr = remoteAddr
+	for name, values := range headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	return got
+}
+
+func TestClientIPIgnoresHeadersFromUntrustedPeers(t *testing.T) {
+	spoofed := map[string][]string{
+		"X-Forwarded-For":   {"1.2.3.4"},
+		"X-Forwarded-Proto": {"https"},
+		"X-Forwarded-Host":  {"evil.example"},
+		"X-Real-IP":         {"1.2.3.4"},
+		"Forwarded":         {"for=1.2.3.4;proto=https;host=evil.example"},
+	}
+
+	// No trusted proxies configured
+	got := resolve(t, nil, "203.0.113.9:4000", spoofed)
+	assert.Equal(t, resolved{"203.0.113.9", "http", "app.internal"}, got)
+
+	// The peer is outside the trusted range
+	got = resolve(t, []string{"10.0.0.0/8"}, "203.0.113.9:4000", spoofed)
+	assert.Equal(t, resolved{"203.0.113.9", "http", "app.internal"}, got)
+}
+
+func TestClientIPXForwardedHeaders(t *testing.T) {
+	proxies := []string{"10.0.0.0/8", "192.168.1.1"}
+
+	got := resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"X-Forwarded-For":   {"198.51.100.7"},
+		"X-Forwarded-Proto": {"https"},
+		"X-Forwarded-Host":  {"shop.example"},
+	})
+	assert.Equal(t, resolved{"198.51.100.7", "https", "shop.example"}, got)
+
+	// The chain is followed through trusted proxies only: the spoofed first
+	// entry is ignored because 203.0.113.5 is not trusted
+	got = resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"X-Forwarded-For": {"6.6.6.6, 203.0.113.5", "192.168.1.1"},
+	})
+	assert.Equal(t, "203.0.113.5", got.ip)
+
+	// When every hop is trusted the oldest one is the client
+	got = resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"X-Forwarded-For": {"10.1.1.1, 10.2.2.2"},
+	})
+	assert.Equal(t, "10.1.1.1", got.ip)
+
+	// Garbage stops the walk at the last valid address
+	got = resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"X-Forwarded-For": {"not-an-ip, 10.2.2.2"},
+	})
+	assert.Equal(t, "10.2.2.2", got.ip)
+}
+
+func TestClientIPForwardedHeader(t *testing.T) {
+	proxies := []string{"10.0.0.0/8"}
+
+	got := resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"Forwarded":       {`for="[2001:db8:cafe::17]:4711";proto=https;host=shop.example, for=10.0.0.9;proto=http;host=internal`},
+		"X-Forwarded-For": {"9.9.9.9"},
+	})
+	assert.Equal(t, resolved{"2001:db8:cafe::17", "https", "shop.example"}, got)
+
+	got = resolve(t, proxies, "10.0.0.2:4000", map[string][]string{
+		"Forwarded": {"for=unknown"},
+	})
+	assert.Equal(t, "10.0.0.2", got.ip)
+}
+
+func TestClientIPRealIP(t *testing.T) {
+	proxies := []string{"127.0.0.1", "::1"}
+
+	got := resolve(t, proxies, "[::1]:4000", map[string][]string{"X-Real-IP": {"198.51.100.7"}})
+	assert.Equal(t, "198.51.100.7", got.ip)
+
+	got = resolve(t, proxies, "127.0.0.1:4000", map[string][]string{"X-Real-IP": {"nonsense"}})
+	assert.Equal(t, "127.0.0.1", got.ip)
+}
+
+func TestSchemeFromTLS(t *testing.T) {
+	app := vayu.New()
+	var scheme string
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		scheme = c.Scheme()
+	})
+	req := httptest.NewRequest("GET", "/", nil)
+	req.TLS = &tls.ConnectionState{}
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, "https", scheme)
+}
+
+func TestSetTrustedProxiesInvalid(t *testing.T) {
+	assert.Error(t, vayu.New().SetTrustedProxies("10.0.0.0/33"))
+	assert.Error(t, vayu.New().SetTrustedProxies("proxy.local"))
+}
+
+func TestAccessLogUsesClientIP(t *testing.T) {
+	var buf bytes.Buffer
+	app := newAccessLogApp(vayu.AccessLogConfig{Output: &buf})
+	assert.NoError(t, app.SetTrustedProxies("10.0.0.0/8"))
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	req.RemoteAddr = "10.0.0.1:5555"
+	req.Header.Set("X-Forwarded-For", "198.51.100.7")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Regexp(t, `^198\.51\.100\.7 - - `, buf.String())
+}
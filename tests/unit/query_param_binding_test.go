@@ -1,6 +1,8 @@
 package unit
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -167,6 +169,7 @@ func TestBindQueryParams(t *testing.T) {
 			Tags       []string      `query:"tags"`
 			Ratings    []int         `query:"ratings"`
 			Timeout    time.Duration `query:"timeout"`
+			Skip       string        `query:"-"`
 		}
 
 		params, err := vayu.BindQueryParams[SearchParams](c)
@@ -182,6 +185,7 @@ func TestBindQueryParams(t *testing.T) {
 			assert.Equal(t, []string{"programming", "backend"}, params.Tags)
 			assert.Equal(t, []int{4, 5}, params.Ratings)
 			assert.Equal(t, 30*time.Second, params.Timeout)
+			assert.Empty(t, params.Skip)
 			c.Writer.WriteHeader(200)
 		} else {
 			c.Writer.WriteHeader(400)
@@ -189,7 +193,7 @@ func TestBindQueryParams(t *testing.T) {
 	})
 
 	// Create a request with multiple query params
-	req, _ := http.NewRequest("GET", "/search?q=golang&page=2&per_page=20&filter=books&sort=price&desc=true&min_price=10.5&tags=programming,backend&ratings=4,5&timeout=30s", nil)
+	req, _ := http.NewRequest("GET", "/search?q=golang&page=2&per_page=20&filter=books&sort=price&desc=true&min_price=10.5&tags=programming,backend&ratings=4,5&timeout=30s&-=zz", nil)
 	resp := httptest.NewRecorder()
 
 	// Serve the request
@@ -248,3 +252,117 @@ func TestBindQueryParamErrors(t *testing.T) {
 
 	assert.Equal(t, 400, respInvalid.Code)
 }
+
+// level is a TextUnmarshaler used to test custom query types
+type level int
+
+func (l *level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("unknown level %q", text)
+	}
+	return nil
+}
+
+type pagination struct {
+	Page    int `query:"page" default:"1"`
+	PerPage int `query:"per_page" default:"20"`
+}
+
+type dateRange struct {
+	From time.Time `query:"from" format:"2006-01-02"`
+	To   time.Time `query:"to" format:"2006-01-02"`
+}
+
+type richSearch struct {
+	pagination
+	Term    *string    `query:"q"`
+	MinAge  *int       `query:"min_age"`
+	Tags    []string   `query:"tag"`
+	IDs     []uint16   `query:"id"`
+	Scores  []float32  `query:"score"`
+	Since   time.Time  `query:"since"`
+	Level   level      `query:"level" default:"low"`
+	Owner   vayu.UUID  `query:"owner"`
+	Created dateRange  `query:"created"`
+	Range   *dateRange `query:"range"`
+	Sort    string     `query:"sort" default:"relevance"`
+}
+
+func bindRichSearch(t *testing.T, rawQuery string) (richSearch, error) {
+	t.Helper()
+	app := vayu.New()
+	var got richSearch
+	var bindErr error
+	app.GET("/search", func(c *vayu.Context, next vayu.NextFunc) {
+		got, bindErr = vayu.BindQueryParams[richSearch](c)
+	})
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/search?"+rawQuery, nil))
+	return got, bindErr
+}
+
+func TestBindQueryParamsRichTypes(t *testing.T) {
+	got, err := bindRichSearch(t, "q=go&min_age=0&tag=a&tag=b,c&id=1&id=65535&score=0.5,1.5"+
+		"&since=2024-03-01T10:00:00Z&level=high&owner=123e4567-e89b-12d3-a456-426614174000"+
+		"&created.from=2024-01-01&created.to=2024-01-31&page=3")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if assert.NotNil(t, got.Term) {
+		assert.Equal(t, "go", *got.Term)
+	}
+	if assert.NotNil(t, got.MinAge) {
+		assert.Equal(t, 0, *got.MinAge)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, got.Tags)
+	assert.Equal(t, []uint16{1, 65535}, got.IDs)
+	assert.Equal(t, []float32{0.5, 1.5}, got.Scores)
+	assert.Equal(t, time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC), got.Since)
+	assert.Equal(t, level(2), got.Level)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", got.Owner.String())
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), got.Created.From)
+	assert.Equal(t, time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), got.Created.To)
+	assert.Nil(t, got.Range)
+
+	// Embedded struct fields use the parent's keys, with defaults
+	assert.Equal(t, 3, got.Page)
+	assert.Equal(t, 20, got.PerPage)
+	assert.Equal(t, "relevance", got.Sort)
+}
+
+func TestBindQueryParamsAbsentValues(t *testing.T) {
+	got, err := bindRichSearch(t, "range.from=2024-05-01")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Nil(t, got.Term)
+	assert.Nil(t, got.MinAge)
+	assert.Nil(t, got.Tags)
+	assert.Equal(t, level(1), got.Level)
+	assert.Equal(t, 1, got.Page)
+	if assert.NotNil(t, got.Range) {
+		assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), got.Range.From)
+		assert.True(t, got.Range.To.IsZero())
+	}
+}
+
+func TestBindQueryParamsRichTypeErrors(t *testing.T) {
+	_, err := bindRichSearch(t, "id=70000&level=medium&created.from=01/02/2024&min_age=x")
+	var be *vayu.BindingError
+	if !assert.True(t, errors.As(err, &be)) {
+		return
+	}
+
+	keys := make([]string, len(be.Errors))
+	for i, fe := range be.Errors {
+		keys[i] = fe.Key
+	}
+	assert.ElementsMatch(t, []string{"id", "level", "created.from", "min_age"}, keys)
+	assert.Contains(t, err.Error(), `query parameter level: cannot convert 'medium' to unit.level: unknown level "medium"`)
+	assert.Contains(t, err.Error(), "query parameter created.from: cannot convert '01/02/2024' to time with format '2006-01-02'")
+}
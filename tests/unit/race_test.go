@@ -0,0 +1,7 @@
+//go:build race
+
+package unit
+
+// raceEnabled reports whether tests run under the race detector, which
+// makes sync.Pool drop items and so changes allocation counts.
+const raceEnabled = true
@@ -0,0 +1,177 @@
+package unit
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+// redirectFrom runs handler for a request to target with the given Referer
+// and returns the recorder and the handler's error.
+func redirectFrom(app *vayu.App, target, referer string, handler func(c *vayu.Context) error) (*httptest.ResponseRecorder, error) {
+	var err error
+	app.GET("/redirect", func(c *vayu.Context, next vayu.NextFunc) {
+		err = handler(c)
+	})
+
+	req := httptest.NewRequest("GET", target, nil)
+	if referer != "" {
+		req.Header.Set("Referer", referer)
+	}
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	return w, err
+}
+
+func TestRedirect(t *testing.T) {
+	w, err := redirectFrom(vayu.New(), "http://shop.example/redirect", "", func(c *vayu.Context) error {
+		return c.Redirect(vayu.StatusSeeOther, "https://elsewhere.example/login")
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, vayu.StatusSeeOther, w.Code)
+	assert.Equal(t, "https://elsewhere.example/login", w.Header().Get("Location"))
+
+	w, err = redirectFrom(vayu.New(), "/redirect", "", func(c *vayu.Context) error {
+		return c.Redirect(vayu.StatusOK, "/home")
+	})
+	assert.Error(t, err)
+	assert.Empty(t, w.Header().Get("Location"))
+}
+
+func TestSafeRedirects(t *testing.T) {
+	config := vayu.SafeRedirectConfig{AllowedHosts: []string{"auth.example", "*.cdn.example"}}
+
+	allowed := []string{
+		"/account",
+		"settings?tab=1",
+		"http://shop.example:8080/cart",
+		"https://SHOP.example/",
+		"https://auth.example/login",
+		"https://img.cdn.example/a.png",
+		"//auth.example/login",
+	}
+	for _, target := range allowed {
+		w, err := redirectFrom(vayu.New().SetSafeRedirects(config), "http://shop.example/redirect", "", func(c *vayu.Context) error {
+			return c.Redirect(vayu.StatusFound, target)
+		})
+		assert.NoError(t, err, target)
+		assert.Equal(t, vayu.StatusFound, w.Code, target)
+	}
+
+	blocked := []string{
+		"https://evil.example/",
+		"//evil.example",
+		"/\\evil.example",
+		"https://auth.example@evil.example/",
+		"https://cdn.example.evil.example/",
+		"https://cdn.example/",
+		"javascript:alert(1)",
+		"https:evil.example",
+		"/ok\r\nSet-Cookie: x=1",
+		"",
+	}
+	for _, target := range blocked {
+		w, err := redirectFrom(vayu.New().SetSafeRedirects(config), "http://shop.example/redirect", "", func(c *vayu.Context) error {
+			return c.Redirect(vayu.StatusFound, target)
+		})
+		assert.True(t, errors.Is(err, vayu.ErrUnsafeRedirect), target)
+		var sc vayu.StatusCoder
+		if assert.True(t, errors.As(err, &sc), target) {
+			assert.Equal(t, vayu.StatusBadRequest, sc.StatusCode())
+		}
+		assert.Empty(t, w.Header().Get("Location"), target)
+	}
+}
+
+func TestSafeRedirectPaths(t *testing.T) {
+	app := vayu.New().SetSafeRedirects(vayu.SafeRedirectConfig{AllowedPaths: []string{"/account/"}})
+	var results []bool
+	redirectFrom(app, "/redirect", "", func(c *vayu.Context) error {
+		for _, target := range []string{"/account", "/account/orders", "/accounts", "/account/../admin", "orders"} {
+			results = append(results, c.IsSafeRedirect(target))
+		}
+		return nil
+	})
+	assert.Equal(t, []bool{true, true, false, false, false}, results)
+}
+
+func TestNamedRoutes(t *testing.T) {
+	app := vayu.New()
+	noop := func(c *vayu.Context, next vayu.NextFunc) {}
+	app.GET("/users/:id/posts/:slug", noop).Name("posts.show")
+	app.Group("/admin").GET("/dashboard", noop).Name("admin.dashboard")
+
+	url, err := app.URL("posts.show", map[string]string{"id": "42", "slug": "hello world/2", "ref": "mail"})
+	assert.NoError(t, err)
+	assert.Equal(t, "/users/42/posts/hello%20world%2F2?ref=mail", url)
+
+	url, err = app.URL("admin.dashboard", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "/admin/dashboard", url)
+
+	_, err = app.URL("posts.show", map[string]string{"id": "42"})
+	assert.Error(t, err)
+	_, err = app.URL("missing", nil)
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { app.Name("posts.show") })
+	assert.Panics(t, func() { vayu.New().Name("first") })
+}
+
+func TestRedirectToRoute(t *testing.T) {
+	app := vayu.New()
+	app.GET("/users/:id", func(c *vayu.Context, next vayu.NextFunc) {}).Name("users.show")
+
+	w, err := redirectFrom(app, "/redirect", "", func(c *vayu.Context) error {
+		return c.RedirectToRoute("users.show", map[string]string{"id": "7"})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, vayu.StatusFound, w.Code)
+	assert.Equal(t, "/users/7", w.Header().Get("Location"))
+
+	w, err = redirectFrom(vayu.New(), "/redirect", "", func(c *vayu.Context) error {
+		return c.RedirectToRoute("users.show", nil)
+	})
+	assert.Error(t, err)
+	assert.Empty(t, w.Header().Get("Location"))
+}
+
+func TestBack(t *testing.T) {
+	back := func(c *vayu.Context) error { return c.Back("/home") }
+
+	w, err := redirectFrom(vayu.New(), "http://shop.example/redirect", "http://shop.example/cart?step=2", back)
+	assert.NoError(t, err)
+	assert.Equal(t, vayu.StatusFound, w.Code)
+	assert.Equal(t, "http://shop.example/cart?step=2", w.Header().Get("Location"))
+
+	// Referers from other sites fall back, even without safe redirects
+	w, _ = redirectFrom(vayu.New(), "http://shop.example/redirect", "https://evil.example/", back)
+	assert.Equal(t, "/home", w.Header().Get("Location"))
+
+	w, _ = redirectFrom(vayu.New(), "http://shop.example/redirect", "", back)
+	assert.Equal(t, "/home", w.Header().Get("Location"))
+
+	// Allowed hosts are followed
+	app := vayu.New().SetSafeRedirects(vayu.SafeRedirectConfig{AllowedHosts: []string{"blog.example"}})
+	w, _ = redirectFrom(app, "http://shop.example/redirect", "https://blog.example/post", back)
+	assert.Equal(t, "https://blog.example/post", w.Header().Get("Location"))
+}
+
+func TestBackUsesForwardedHost(t *testing.T) {
+	app := vayu.New()
+	assert.NoError(t, app.SetTrustedProxies("10.0.0.0/8"))
+	app.GET("/redirect", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Back("/home")
+	})
+
+	req := httptest.NewRequest("GET", "http://backend:8080/redirect", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-Host", "shop.example")
+	req.Header.Set("Referer", "https://shop.example/cart")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	assert.Equal(t, "https://shop.example/cart", w.Header().Get("Location"))
+}
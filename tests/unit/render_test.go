@@ -0,0 +1,287 @@
+package unit
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type exportedOrder struct {
+	ID       int        `json:"id" xml:"id" yaml:"id" csv:"order_id"`
+	Customer string     `json:"customer" xml:"customer" yaml:"customer" csv:"customer"`
+	Total    float64    `json:"total" xml:"total" yaml:"total" csv:"total"`
+	Placed   time.Time  `json:"-" xml:"-" yaml:"-" csv:"placed" format:"2006-01-02"`
+	Shipped  *time.Time `json:"-" xml:"-" yaml:"-" csv:"shipped"`
+	Notes    string     `json:"-" xml:"-" yaml:"-" csv:"-"`
+}
+
+// serve runs handler for a GET request and returns the recorder.
+func serve(handler func(c *vayu.Context)) *httptest.ResponseRecorder {
+	app := vayu.New()
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		handler(c)
+	})
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	return w
+}
+
+func TestXMLAndYAMLResponses(t *testing.T) {
+	order := exportedOrder{ID: 7, Customer: "Ada", Total: 9.5}
+
+	w := serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.XMLResponse(c, vayu.StatusOK, order))
+	})
+	assert.Equal(t, "application/xml", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<exportedOrder><id>7</id><customer>Ada</customer><total>9.5</total></exportedOrder>`, w.Body.String())
+
+	w = serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.YAMLResponse(c, vayu.StatusCreated, order))
+	})
+	assert.Equal(t, vayu.StatusCreated, w.Code)
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+	assert.Equal(t, "id: 7\ncustomer: Ada\ntotal: 9.5\n", w.Body.String())
+
+	// Encoding errors are returned before anything is written
+	w = serve(func(c *vayu.Context) {
+		assert.Error(t, c.XML(vayu.StatusOK, map[string]int{"a": 1}))
+	})
+	assert.Empty(t, w.Body.String())
+}
+
+func TestCSVResponse(t *testing.T) {
+	shipped := time.Date(2026, 3, 2, 15, 4, 5, 0, time.UTC)
+	orders := []exportedOrder{
+		{ID: 1, Customer: "Ada", Total: 12.25, Placed: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Shipped: &shipped, Notes: "secret"},
+		{ID: 2, Customer: `Smith, "Jr"`, Total: 3, Placed: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)},
+	}
+
+	w := serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.CSVResponse(c, vayu.StatusOK, orders))
+	})
+	assert.Equal(t, "text/csv", w.Header().Get("Content-Type"))
+	assert.Equal(t, "order_id,customer,total,placed,shipped\n"+
+		"1,Ada,12.25,2026-03-01,2026-03-02T15:04:05Z\n"+
+		"2,\"Smith, \"\"Jr\"\"\",3,2026-03-02,\n", w.Body.String())
+
+	// Pointers to structs, embedded structs and untagged fields
+	type audit struct {
+		By string `csv:"by"`
+	}
+	type row struct {
+		audit
+		Name string
+		ID   vayu.UUID `csv:"id"`
+	}
+	id, _ := vayu.ParseUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	w = serve(func(c *vayu.Context) {
+		assert.NoError(t, c.CSV(vayu.StatusOK, []*row{{Name: "a", ID: id}, nil}))
+	})
+	assert.Equal(t, "Name,id\na,6ba7b810-9dad-11d1-80b4-00c04fd430c8\n", w.Body.String())
+
+	w = serve(func(c *vayu.Context) {
+		assert.NoError(t, c.CSV(vayu.StatusOK, [][]string{{"a", "b"}, {"1", "2"}}))
+	})
+	assert.Equal(t, "a,b\n1,2\n", w.Body.String())
+
+	// Unsupported rows and columns are rejected up front
+	w = serve(func(c *vayu.Context) {
+		assert.Error(t, c.CSV(vayu.StatusOK, []int{1, 2}))
+		assert.Error(t, c.CSV(vayu.StatusOK, exportedOrder{}))
+		assert.Error(t, c.CSV(vayu.StatusOK, []struct{ Tags []string }{{}}))
+	})
+	assert.Empty(t, w.Body.String())
+}
+
+func TestMsgPackResponse(t *testing.T) {
+	type item struct {
+		Name  string            `msgpack:"name"`
+		Qty   int               `json:"qty"`
+		Price float64           `msgpack:"price,omitempty"`
+		Tags  []string          `msgpack:"tags"`
+		Attrs map[string]uint16 `msgpack:"attrs"`
+		Raw   []byte            `msgpack:"raw"`
+		Skip  bool              `msgpack:"-"`
+		Neg   int64             `msgpack:"neg"`
+		When  time.Time         `msgpack:"when"`
+		Next  *item             `msgpack:"next"`
+	}
+
+	w := serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.MsgPackResponse(c, vayu.StatusOK, item{
+			Name:  "pen",
+			Qty:   300,
+			Tags:  []string{"a"},
+			Attrs: map[string]uint16{"w": 2, "h": 1000},
+			Raw:   []byte{1, 2},
+			Neg:   -200,
+			When:  time.Unix(1, 0),
+		}))
+	})
+	assert.Equal(t, "application/msgpack", w.Header().Get("Content-Type"))
+
+	expected := "88" + // map with 8 entries
+		"a46e616d65" + "a370656e" + // name: "pen"
+		"a3717479" + "cd012c" + // qty: uint16 300
+		"a474616773" + "91a161" + // tags: ["a"]
+		"a56174747273" + "82a168cd03e8a17702" + // attrs: sorted {h: 1000, w: 2}
+		"a3726177" + "c4020102" + // raw: bin 8
+		"a36e6567" + "d1ff38" + // neg: int16 -200
+		"a47768656e" + "d6ff00000001" + // when: timestamp 32
+		"a46e657874" + "c0" // next: nil
+	assert.Equal(t, expected, hex.EncodeToString(w.Body.Bytes()))
+}
+
+func TestMarshalMsgPackFormats(t *testing.T) {
+	cases := []struct {
+		value    any
+		expected string
+	}{
+		{nil, "c0"},
+		{true, "c3"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{int64(-1 << 40), "d3ffffff0000000000"},
+		{uint64(1 << 40), "cf0000010000000000"},
+		{float32(1.5), "ca3fc00000"},
+		{1.5, "cb3ff8000000000000"},
+		{strings.Repeat("x", 32), "d920" + strings.Repeat("78", 32)},
+		{[]int(nil), "c0"},
+		{[2]int{1, 2}, "920102"},
+		{time.Unix(1, 5), "d7ff0000001400000001"},
+	}
+	for _, tc := range cases {
+		out, err := vayu.MarshalMsgPack(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.expected, hex.EncodeToString(out), "%#v", tc.value)
+	}
+
+	_, err := vayu.MarshalMsgPack(map[string]any{"f": func() {}})
+	assert.Error(t, err)
+}
+
+type msgPackStamp struct {
+	At time.Time `msgpack:"at"`
+}
+
+type msgPackMeta struct {
+	Updated time.Time `msgpack:"updated"`
+}
+
+func TestMarshalMsgPackUnexportedEmbedded(t *testing.T) {
+	type event struct {
+		msgPackStamp
+		*msgPackMeta
+		Name string `msgpack:"name"`
+	}
+
+	out, err := vayu.MarshalMsgPack(event{msgPackStamp{time.Unix(1, 0)}, &msgPackMeta{time.Unix(2, 0)}, "x"})
+	assert.NoError(t, err)
+	expected := "83" + // map with 3 entries
+		"a26174" + "d6ff00000001" + // at: timestamp 32
+		"a775706461746564" + "d6ff00000002" + // updated: timestamp 32
+		"a46e616d65" + "a178" // name: "x"
+	assert.Equal(t, expected, hex.EncodeToString(out))
+}
+
+func TestMarshalMsgPackCycles(t *testing.T) {
+	type node struct {
+		Next *node
+	}
+	self := &node{}
+	self.Next = self
+
+	loop := map[string]any{}
+	loop["self"] = loop
+
+	nested := []any{nil}
+	nested[0] = nested
+
+	for _, value := range []any{self, loop, nested} {
+		done := make(chan error, 1)
+		go func() {
+			_, err := vayu.MarshalMsgPack(value)
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			assert.ErrorContains(t, err, "encountered a cycle")
+		case <-time.After(5 * time.Second):
+			t.Fatalf("MarshalMsgPack did not terminate on %T", value)
+		}
+	}
+
+	// A value shared by several fields is not a cycle
+	shared := &node{}
+	out, err := vayu.MarshalMsgPack([]*node{shared, shared})
+	assert.NoError(t, err)
+	assert.Equal(t, "92"+"81a44e657874c0"+"81a44e657874c0", hex.EncodeToString(out))
+}
+
+func TestNDJSONStreaming(t *testing.T) {
+	events := []exportedOrder{{ID: 1, Customer: "Ada"}, {ID: 2, Customer: "Bob"}}
+
+	w := serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.NDJSON(c, vayu.StatusOK, slices.Values(events)))
+	})
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":1,"customer":"Ada","total":0}`+"\n"+`{"id":2,"customer":"Bob","total":0}`+"\n", w.Body.String())
+	assert.True(t, w.Flushed)
+
+	ch := make(chan int, 3)
+	ch <- 1
+	ch <- 2
+	close(ch)
+	w = serve(func(c *vayu.Context) {
+		assert.NoError(t, vayu.NDJSONChannel(c, vayu.StatusOK, ch))
+	})
+	assert.Equal(t, "1\n2\n", w.Body.String())
+}
+
+func TestNDJSONStopsWhenRequestIsCancelled(t *testing.T) {
+	app := vayu.New()
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int) // never closed
+
+	var streamErr error
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		go func() {
+			ch <- 1
+			ch <- 2 // received once 1 was written
+			cancel()
+		}()
+		streamErr = vayu.NDJSONChannel(c, vayu.StatusOK, ch)
+	})
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil).WithContext(ctx))
+
+	assert.True(t, errors.Is(streamErr, context.Canceled))
+	assert.True(t, strings.HasPrefix(w.Body.String(), "1\n"))
+}
+
+func TestNegotiateExtraFormats(t *testing.T) {
+	orders := []exportedOrder{{ID: 1, Customer: "Ada", Total: 2}}
+
+	w, err := negotiate(t, "text/csv", orders)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(w.Body.String(), "order_id,customer,total"))
+
+	w, _ = negotiate(t, "application/yaml", orders[0])
+	assert.Equal(t, "application/yaml", w.Header().Get("Content-Type"))
+
+	w, _ = negotiate(t, "application/msgpack", 1)
+	assert.Equal(t, []byte{1}, w.Body.Bytes())
+
+	// A single struct has no CSV form
+	_, err = negotiate(t, "text/csv", orders[0])
+	assert.True(t, errors.Is(err, vayu.ErrNotAcceptable))
+}
@@ -0,0 +1,156 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestIDGenerated(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.RequestID(vayu.RequestIDConfig{}))
+
+	var handlerID, ctxID string
+	app.GET("/id", func(c *vayu.Context, next vayu.NextFunc) {
+		handlerID = c.RequestID()
+		ctxID = vayu.RequestIDFromContext(c.Request.Context())
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/id", nil))
+
+	if !uuidPattern.MatchString(handlerID) {
+		t.Errorf("Expected generated UUID request ID, got '%s'", handlerID)
+	}
+	if ctxID != handlerID {
+		t.Errorf("Expected request context to carry ID '%s', got '%s'", handlerID, ctxID)
+	}
+	if got := w.Header().Get(vayu.HeaderXRequestID); got != handlerID {
+		t.Errorf("Expected response header '%s', got '%s'", handlerID, got)
+	}
+}
+
+func TestRequestIDInbound(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.RequestID(vayu.RequestIDConfig{Header: "X-Correlation-ID", MaxLength: 16}))
+
+	var handlerID string
+	app.GET("/id", func(c *vayu.Context, next vayu.NextFunc) {
+		handlerID = c.RequestID()
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	tests := []struct {
+		name    string
+		inbound string
+		reused  bool
+	}{
+		{"Valid", "abc-123_XYZ", true},
+		{"TooLong", strings.Repeat("a", 17), false},
+		{"InvalidCharset", "abc\ninjected", false},
+		{"Spaces", "abc def", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/id", nil)
+			req.Header.Set("X-Correlation-ID", tt.inbound)
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, req)
+
+			if tt.reused && handlerID != tt.inbound {
+				t.Errorf("Expected inbound ID '%s' to be reused, got '%s'", tt.inbound, handlerID)
+			}
+			if !tt.reused && (handlerID == tt.inbound || !uuidPattern.MatchString(handlerID)) {
+				t.Errorf("Expected inbound ID to be replaced by a UUID, got '%s'", handlerID)
+			}
+			if got := w.Header().Get("X-Correlation-ID"); got != handlerID {
+				t.Errorf("Expected response header '%s', got '%s'", handlerID, got)
+			}
+		})
+	}
+}
+
+func TestRequestIDLoggerAndErrorHandler(t *testing.T) {
+	var logBuffer bytes.Buffer
+	app := vayu.New()
+	app.SetLogger(slog.New(slog.NewTextHandler(&logBuffer, nil)))
+	app.Use(vayu.RequestID(vayu.RequestIDConfig{Generator: func() string { return "fixed-id" }}))
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.GET("/fail", func(c *vayu.Context, next vayu.NextFunc) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/fail", nil))
+
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Failed to decode error response: %v", err)
+	}
+	if body["request_id"] != "fixed-id" {
+		t.Errorf("Expected error response to include request_id 'fixed-id', got %v", body)
+	}
+	if !strings.Contains(logBuffer.String(), "request_id=fixed-id") {
+		t.Errorf("Expected logs to include the request ID, got: %s", logBuffer.String())
+	}
+}
+
+func TestPropagateRequestID(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.RequestID(vayu.RequestIDConfig{Header: "X-Trace"}))
+
+	var outbound *http.Request
+	app.GET("/call", func(c *vayu.Context, next vayu.NextFunc) {
+		outbound, _ = http.NewRequest("GET", "http://upstream.local/", nil)
+		c.PropagateRequestID(outbound)
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/call", nil)
+	req.Header.Set("X-Trace", "upstream-42")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := outbound.Header.Get("X-Trace"); got != "upstream-42" {
+		t.Errorf("Expected outbound X-Trace 'upstream-42', got '%s'", got)
+	}
+}
+
+func TestRequestIDFallbackValidated(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	var handlerID string
+	app.GET("/id", func(c *vayu.Context, next vayu.NextFunc) {
+		handlerID = c.RequestID()
+		panic("boom")
+	})
+
+	req := httptest.NewRequest("GET", "/id", nil)
+	req.Header.Set(vayu.HeaderXRequestID, "<script>alert(1)</script>")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+
+	if handlerID != "" {
+		t.Errorf("Expected an invalid inbound ID to be ignored, got '%s'", handlerID)
+	}
+	if strings.Contains(w.Body.String(), "script") {
+		t.Errorf("Expected the inbound ID not to be echoed, got %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/id", nil)
+	req.Header.Set(vayu.HeaderXRequestID, "upstream-42")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+	if handlerID != "upstream-42" {
+		t.Errorf("Expected a valid inbound ID to be used, got '%s'", handlerID)
+	}
+}
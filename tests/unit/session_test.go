@@ -0,0 +1,282 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionClient replays the session cookie between requests like a browser.
+type sessionClient struct {
+	app    *vayu.App
+	cookie *http.Cookie
+}
+
+func (sc *sessionClient) get(path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if sc.cookie != nil {
+		req.AddCookie(sc.cookie)
+	}
+	resp := httptest.NewRecorder()
+	sc.app.ServeHTTP(resp, req)
+
+	if cookie := responseCookie(resp, vayu.DefaultSessionCookie); cookie != nil {
+		if cookie.MaxAge < 0 {
+			sc.cookie = nil
+		} else {
+			sc.cookie = cookie
+		}
+	}
+	return resp
+}
+
+func sessionApp(store vayu.Store, maxAge time.Duration) *vayu.App {
+	app := vayu.New()
+	app.Use(vayu.Sessions(vayu.SessionConfig{Store: store, MaxAge: maxAge}))
+
+	app.GET("/login", func(c *vayu.Context, next vayu.NextFunc) {
+		s := c.Session()
+		s.Regenerate()
+		s.Set("user", "ada")
+		s.Flash("notice", "Welcome back")
+		c.OK(map[string]string{"id": s.ID()})
+	})
+	app.GET("/whoami", func(c *vayu.Context, next vayu.NextFunc) {
+		user, _ := vayu.SessionValue[string](c, "user")
+		c.OK(map[string]any{
+			"user":    user,
+			"new":     c.Session().IsNew(),
+			"id":      c.Session().ID(),
+			"notices": c.Session().Flashes("notice"),
+		})
+	})
+	app.GET("/logout", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Session().Destroy()
+		c.Writer.WriteHeader(vayu.StatusNoContent)
+	})
+	app.GET("/silent", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Session().Set("visited", true)
+	})
+	return app
+}
+
+type whoami struct {
+	User    string `json:"user"`
+	New     bool   `json:"new"`
+	ID      string `json:"id"`
+	Notices []any  `json:"notices"`
+}
+
+func decodeWhoami(t *testing.T, resp *httptest.ResponseRecorder) whoami {
+	t.Helper()
+	var w whoami
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &w))
+	return w
+}
+
+func TestSessionsMemoryStore(t *testing.T) {
+	store := vayu.NewMemoryStore(0)
+	defer store.Close()
+	client := &sessionClient{app: sessionApp(store, 0)}
+
+	// Anonymous requests do not create sessions
+	w := decodeWhoami(t, client.get("/whoami"))
+	assert.True(t, w.New)
+	assert.Nil(t, client.cookie)
+	assert.Equal(t, 0, store.Len())
+
+	client.get("/login")
+	if !assert.NotNil(t, client.cookie) {
+		return
+	}
+	assert.True(t, client.cookie.HttpOnly)
+	assert.True(t, client.cookie.Secure)
+	assert.Equal(t, int(vayu.DefaultSessionMaxAge/time.Second), client.cookie.MaxAge)
+	assert.Equal(t, 1, store.Len())
+
+	w = decodeWhoami(t, client.get("/whoami"))
+	assert.Equal(t, "ada", w.User)
+	assert.False(t, w.New)
+	assert.Equal(t, []any{"Welcome back"}, w.Notices)
+
+	// Flashes are read once
+	w = decodeWhoami(t, client.get("/whoami"))
+	assert.Empty(t, w.Notices)
+	assert.Equal(t, "ada", w.User)
+
+	client.get("/logout")
+	assert.Nil(t, client.cookie)
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestSessionRegenerate(t *testing.T) {
+	store := vayu.NewMemoryStore(0)
+	defer store.Close()
+	client := &sessionClient{app: sessionApp(store, 0)}
+
+	client.get("/silent")
+	before := decodeWhoami(t, client.get("/whoami")).ID
+	assert.NotEmpty(t, before)
+
+	stolen := client.cookie
+	client.get("/login")
+	after := decodeWhoami(t, client.get("/whoami"))
+	assert.NotEqual(t, before, after.ID)
+	assert.Equal(t, "ada", after.User)
+	assert.Equal(t, 1, store.Len())
+
+	// The pre-login session ID no longer works
+	attacker := &sessionClient{app: client.app, cookie: stolen}
+	w := decodeWhoami(t, attacker.get("/whoami"))
+	assert.True(t, w.New)
+	assert.Empty(t, w.User)
+}
+
+func TestSessionsSavedWithoutBody(t *testing.T) {
+	store := vayu.NewMemoryStore(0)
+	defer store.Close()
+	client := &sessionClient{app: sessionApp(store, 0)}
+
+	client.get("/silent")
+	assert.NotNil(t, client.cookie)
+	assert.Equal(t, 1, store.Len())
+}
+
+func TestMemoryStoreExpiry(t *testing.T) {
+	store := vayu.NewMemoryStore(time.Hour)
+	defer store.Close()
+	client := &sessionClient{app: sessionApp(store, 50*time.Millisecond)}
+
+	client.get("/login")
+	assert.Equal(t, "ada", decodeWhoami(t, client.get("/whoami")).User)
+
+	time.Sleep(80 * time.Millisecond)
+	assert.Empty(t, decodeWhoami(t, client.get("/whoami")).User)
+
+	store.Sweep()
+	assert.Equal(t, 0, store.Len())
+}
+
+func TestSessionsCookieStore(t *testing.T) {
+	store := vayu.NewCookieStore(vayu.MustNewCookieKeys(newSecret))
+	client := &sessionClient{app: sessionApp(store, 0)}
+
+	resp := client.get("/login")
+	var login map[string]string
+	json.Unmarshal(resp.Body.Bytes(), &login)
+	if !assert.NotNil(t, client.cookie) {
+		return
+	}
+	assert.NotContains(t, client.cookie.Value, "ada")
+
+	w := decodeWhoami(t, client.get("/whoami"))
+	assert.Equal(t, "ada", w.User)
+	assert.Equal(t, login["id"], w.ID)
+	assert.Equal(t, []any{"Welcome back"}, w.Notices)
+
+	// A tampered cookie starts a fresh session
+	forged := *client.cookie
+	forged.Value = "x" + forged.Value[1:]
+	attacker := &sessionClient{app: client.app, cookie: &forged}
+	w = decodeWhoami(t, attacker.get("/whoami"))
+	assert.True(t, w.New)
+	assert.Empty(t, w.User)
+}
+
+// fakeStore is a server-side store in the shape of a Redis-backed one: it
+// keeps serialized sessions by ID.
+type fakeStore struct {
+	mu      sync.Mutex
+	data    map[string][]byte
+	deletes []string
+}
+
+func (f *fakeStore) Load(_ context.Context, token string) (*vayu.SessionData, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	raw, ok := f.data[token]
+	if !ok {
+		return nil, vayu.ErrSessionNotFound
+	}
+	data := &vayu.SessionData{}
+	return data, json.Unmarshal(raw, data)
+}
+
+func (f *fakeStore) Save(_ context.Context, data *vayu.SessionData) (string, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.data[data.ID] = raw
+	return data.ID, nil
+}
+
+func (f *fakeStore) Delete(_ context.Context, id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.data, id)
+	f.deletes = append(f.deletes, id)
+	return nil
+}
+
+func TestSessionsCustomStore(t *testing.T) {
+	store := &fakeStore{data: make(map[string][]byte)}
+	client := &sessionClient{app: sessionApp(store, 0)}
+
+	client.get("/silent")
+	firstID := client.cookie.Value
+	client.get("/login")
+
+	assert.Equal(t, []string{firstID}, store.deletes)
+	assert.Len(t, store.data, 1)
+	assert.Equal(t, "ada", decodeWhoami(t, client.get("/whoami")).User)
+}
+
+func TestSessionDestroyClearsScopedCookie(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.Sessions(vayu.SessionConfig{
+		Store:    vayu.NewMemoryStore(0),
+		Path:     "/account",
+		Domain:   "example.com",
+		Insecure: true,
+		SameSite: http.SameSiteStrictMode,
+	}))
+	app.GET("/account/login", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Session().Set("user", "ada")
+	})
+	app.GET("/account/logout", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Session().Destroy()
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/account/login", nil))
+	set := responseCookie(resp, vayu.DefaultSessionCookie)
+	if !assert.NotNil(t, set) {
+		return
+	}
+
+	req := httptest.NewRequest("GET", "/account/logout", nil)
+	req.AddCookie(set)
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	cleared := responseCookie(resp, vayu.DefaultSessionCookie)
+	if assert.NotNil(t, cleared) {
+		assert.Equal(t, -1, cleared.MaxAge)
+		assert.Equal(t, set.Path, cleared.Path)
+		assert.Equal(t, set.Domain, cleared.Domain)
+		assert.Equal(t, set.Secure, cleared.Secure)
+		assert.Equal(t, set.SameSite, cleared.SameSite)
+		assert.Equal(t, "/account", cleared.Path)
+		assert.False(t, cleared.Secure)
+	}
+}
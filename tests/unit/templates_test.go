@@ -0,0 +1,176 @@
+package unit
+
+import (
+	"errors"
+	"html/template"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+func viewsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"views/layouts/main.html": {Data: []byte(`<title>{{block "title" .}}Vayu{{end}}</title>{{template "partials/nav" .}}<main>{{template "content" .}}</main>`)},
+		"views/partials/nav.html": {Data: []byte(`<nav>{{upper .User}}</nav>`)},
+		"views/users/show.html":   {Data: []byte(`{{define "title"}}User {{.User}}{{end}}<h1>{{.User}}</h1>`)},
+		"views/home.html":         {Data: []byte(`<p>home</p>`)},
+		"views/notes.txt":         {Data: []byte(`ignored`)},
+	}
+}
+
+// renderPage loads templates with config and returns the response for a
+// handler calling render.
+func renderPage(t *testing.T, config vayu.TemplateConfig, render func(c *vayu.Context) error) (*httptest.ResponseRecorder, error) {
+	t.Helper()
+	app := vayu.New()
+	if err := app.LoadTemplates(config); err != nil {
+		t.Fatalf("LoadTemplates: %v", err)
+	}
+
+	var renderErr error
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		renderErr = render(c)
+	})
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	return w, renderErr
+}
+
+func TestRenderWithLayoutAndPartials(t *testing.T) {
+	config := vayu.TemplateConfig{
+		FS:     viewsFS(),
+		Root:   "views",
+		Layout: "layouts/main",
+		Funcs:  template.FuncMap{"upper": strings.ToUpper},
+	}
+	data := map[string]string{"User": "<ada>"}
+
+	w, err := renderPage(t, config, func(c *vayu.Context) error {
+		return c.Render(vayu.StatusOK, "users/show", data)
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, vayu.StatusOK, w.Code)
+	assert.Equal(t, "text/html", w.Header().Get("Content-Type"))
+	assert.Equal(t, `<title>User &lt;ada&gt;</title><nav>&lt;ADA&gt;</nav><main><h1>&lt;ada&gt;</h1></main>`, w.Body.String())
+
+	// Blocks not overridden by the page keep the layout's default
+	w, _ = renderPage(t, config, func(c *vayu.Context) error {
+		return c.Render(vayu.StatusCreated, "home", data)
+	})
+	assert.Equal(t, vayu.StatusCreated, w.Code)
+	assert.Equal(t, `<title>Vayu</title><nav>&lt;ADA&gt;</nav><main><p>home</p></main>`, w.Body.String())
+
+	// Without a layout the page renders on its own, escaped only once
+	w, _ = renderPage(t, config, func(c *vayu.Context) error {
+		return c.RenderLayout(vayu.StatusOK, "", "users/show", data)
+	})
+	assert.Equal(t, `<h1>&lt;ada&gt;</h1>`, w.Body.String())
+}
+
+func TestRenderErrors(t *testing.T) {
+	config := vayu.TemplateConfig{FS: viewsFS(), Root: "views", Funcs: template.FuncMap{"upper": strings.ToUpper}}
+
+	w, err := renderPage(t, config, func(c *vayu.Context) error {
+		return c.Render(vayu.StatusOK, "users/missing", nil)
+	})
+	assert.True(t, errors.Is(err, vayu.ErrTemplateNotFound))
+	assert.Empty(t, w.Body.String())
+
+	_, err = renderPage(t, config, func(c *vayu.Context) error {
+		return c.RenderLayout(vayu.StatusOK, "layouts/missing", "home", nil)
+	})
+	assert.True(t, errors.Is(err, vayu.ErrTemplateNotFound))
+
+	// Partials cannot be rendered as pages
+	_, err = renderPage(t, config, func(c *vayu.Context) error {
+		return c.Render(vayu.StatusOK, "partials/nav", nil)
+	})
+	assert.True(t, errors.Is(err, vayu.ErrTemplateNotFound))
+
+	// Execution errors leave the response untouched
+	w, err = renderPage(t, config, func(c *vayu.Context) error {
+		return c.Render(vayu.StatusOK, "users/show", 42)
+	})
+	assert.Error(t, err)
+	assert.Empty(t, w.Body.String())
+
+	app := vayu.New()
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		err = c.Render(vayu.StatusOK, "home", nil)
+	})
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	assert.True(t, errors.Is(err, vayu.ErrNoTemplates))
+}
+
+func TestLoadTemplatesErrors(t *testing.T) {
+	app := vayu.New()
+	assert.Error(t, app.LoadTemplates(vayu.TemplateConfig{}))
+	assert.Error(t, app.LoadTemplates(vayu.TemplateConfig{FS: viewsFS(), Root: "missing"}))
+
+	broken := fstest.MapFS{"bad.html": {Data: []byte(`{{if}}`)}}
+	err := app.LoadTemplates(vayu.TemplateConfig{FS: broken})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "bad.html")
+	}
+
+	// Functions must be registered before parsing
+	err = app.LoadTemplates(vayu.TemplateConfig{FS: viewsFS(), Root: "views"})
+	assert.Error(t, err)
+}
+
+func TestRenderReload(t *testing.T) {
+	dir := t.TempDir()
+	page := filepath.Join(dir, "home.html")
+	assert.NoError(t, os.WriteFile(page, []byte(`v1`), 0o644))
+
+	newApp := func(interval time.Duration) func() string {
+		app := vayu.New()
+		assert.NoError(t, app.LoadTemplates(vayu.TemplateConfig{FS: os.DirFS(dir), Reload: true, ReloadInterval: interval}))
+		app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+			if err := c.Render(vayu.StatusOK, "home", nil); err != nil {
+				c.Send(vayu.StatusInternalServerError, err.Error())
+			}
+		})
+		return func() string {
+			w := httptest.NewRecorder()
+			app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+			return w.Body.String()
+		}
+	}
+	body := newApp(-1)
+	throttled := newApp(time.Hour)
+	assert.Equal(t, "v1", body())
+	assert.Equal(t, "v1", throttled())
+
+	assert.NoError(t, os.WriteFile(page, []byte(`v2`), 0o644))
+	assert.NoError(t, os.Chtimes(page, time.Now(), time.Now().Add(time.Hour)))
+	assert.Equal(t, "v2", body())
+	// Changes are only seen once the interval since the last check passed
+	assert.Equal(t, "v1", throttled())
+
+	// New pages are picked up too, and parse errors are reported
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "about.html"), []byte(`{{if}}`), 0o644))
+	assert.Contains(t, body(), "about.html")
+}
+
+func TestViewRendererNegotiation(t *testing.T) {
+	app := vayu.New()
+	views := fstest.MapFS{"user.html": {Data: []byte(`<b>{{.Name}}</b>`)}}
+	assert.NoError(t, app.LoadTemplates(vayu.TemplateConfig{FS: views}))
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Negotiate(vayu.StatusOK, negotiatedUser{1, "Ada"}, vayu.Offer{MediaType: "text/html", Renderer: vayu.ViewRenderer("user")})
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("Accept", "text/html,*/*;q=0.8")
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, req)
+	assert.Equal(t, "<b>Ada</b>", w.Body.String())
+}
@@ -0,0 +1,250 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+func TestWithTimeoutDoesNotLeakGoroutines(t *testing.T) {
+	app := vayu.New()
+
+	var scoped []context.Context
+	app.GET("/scopes", func(c *vayu.Context, next vayu.NextFunc) {
+		for i := 0; i < 100; i++ {
+			// Deliberately never call the returned function
+			c.WithTimeout(time.Hour)
+			scoped = append(scoped, c.Ctx)
+		}
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/scopes", nil))
+	}
+
+	// Give any stray goroutines a chance to show up
+	time.Sleep(10 * time.Millisecond)
+	if after := runtime.NumGoroutine(); after > before+2 {
+		t.Errorf("Expected no leaked goroutines, had %d before and %d after", before, after)
+	}
+
+	for i, ctx := range scoped {
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Fatalf("Expected scope %d to be cancelled when the request finished, got %v", i, ctx.Err())
+		}
+	}
+}
+
+func TestWithTimeoutScopes(t *testing.T) {
+	app := vayu.New()
+	app.RequestTimeout = time.Second
+
+	var (
+		requestDeadline time.Time
+		innerDeadline   time.Time
+		clampedDeadline time.Time
+		restored        time.Time
+		innerErr        error
+	)
+	app.GET("/nested", func(c *vayu.Context, next vayu.NextFunc) {
+		requestDeadline, _ = c.Deadline()
+
+		restoreInner := c.WithTimeout(100 * time.Millisecond)
+		innerDeadline, _ = c.Deadline()
+		inner := c.Ctx
+
+		// A longer scope cannot extend the enclosing deadline
+		restoreClamped := c.WithTimeout(time.Hour)
+		clampedDeadline, _ = c.Deadline()
+		restoreClamped()
+
+		restoreInner()
+		innerErr = inner.Err()
+		restored, _ = c.Request.Context().Deadline()
+
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/nested", nil))
+
+	if w.Code != vayu.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", vayu.StatusOK, w.Code)
+	}
+	if !innerDeadline.Before(requestDeadline) {
+		t.Errorf("Expected inner deadline %v before request deadline %v", innerDeadline, requestDeadline)
+	}
+	if !clampedDeadline.Equal(innerDeadline) {
+		t.Errorf("Expected nested deadline to be clamped to %v, got %v", innerDeadline, clampedDeadline)
+	}
+	if !errors.Is(innerErr, context.Canceled) {
+		t.Errorf("Expected restore to cancel the scope early, got %v", innerErr)
+	}
+	if !restored.Equal(requestDeadline) {
+		t.Errorf("Expected restore to bring back deadline %v, got %v", requestDeadline, restored)
+	}
+}
+
+func TestTimeoutMiddlewareFastHandler(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.Timeout(vayu.TimeoutConfig{Timeout: time.Second}))
+
+	var afterChain string
+	app.Use(func(c *vayu.Context, next vayu.NextFunc) {
+		next()
+		afterChain, _ = vayu.GetValue[string](c, "handler")
+	})
+	app.GET("/fast", func(c *vayu.Context, next vayu.NextFunc) {
+		c.Set("handler", "ran")
+		c.Writer.Header().Set("X-Handler", "fast")
+		c.Send(vayu.StatusCreated, "done")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/fast", nil))
+
+	if w.Code != vayu.StatusCreated {
+		t.Errorf("Expected status code %d, got %d", vayu.StatusCreated, w.Code)
+	}
+	if w.Body.String() != "done" {
+		t.Errorf("Expected body 'done', got '%s'", w.Body.String())
+	}
+	if w.Header().Get("X-Handler") != "fast" {
+		t.Errorf("Expected buffered header to be sent, got '%s'", w.Header().Get("X-Handler"))
+	}
+	if afterChain != "ran" {
+		t.Errorf("Expected store values set in the handler to be visible to middleware, got '%s'", afterChain)
+	}
+}
+
+func TestTimeoutMiddlewareKeepsEarlierHeaders(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.RequestID(vayu.RequestIDConfig{}))
+	app.Use(func(c *vayu.Context, next vayu.NextFunc) {
+		c.Writer.Header().Set("X-Stale", "1")
+		next()
+	})
+	app.Use(vayu.Timeout(vayu.TimeoutConfig{Timeout: time.Second}))
+
+	var seen string
+	app.GET("/id", func(c *vayu.Context, next vayu.NextFunc) {
+		seen = c.Writer.Header().Get(vayu.HeaderXRequestID)
+		c.Writer.Header().Del("X-Stale")
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/id", nil))
+
+	id := w.Header().Get(vayu.HeaderXRequestID)
+	if id == "" || seen != id {
+		t.Errorf("Expected the handler to see request ID header '%s', got '%s'", id, seen)
+	}
+	if got := w.Header().Get("X-Stale"); got != "" {
+		t.Errorf("Expected the header deleted by the handler to be gone, got '%s'", got)
+	}
+}
+
+func TestTimeoutMiddlewareSlowHandler(t *testing.T) {
+	app := vayu.New()
+
+	var loggedStatus int
+	app.Use(func(c *vayu.Context, next vayu.NextFunc) {
+		next()
+		loggedStatus = c.Writer.Status()
+	})
+
+	release := make(chan struct{})
+	lateWrite := make(chan error, 1)
+	slow := func(c *vayu.Context, next vayu.NextFunc) {
+		<-release
+		_, err := c.Send(vayu.StatusOK, "too late")
+		lateWrite <- err
+	}
+
+	app.GET("/slow", vayu.WithMiddleware(slow, vayu.Timeout(vayu.TimeoutConfig{
+		Timeout:     20 * time.Millisecond,
+		StatusCode:  vayu.StatusServiceUnavailable,
+		Body:        "try again later",
+		ContentType: "text/plain",
+	})))
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/slow", nil))
+	elapsed := time.Since(start)
+
+	// Let the abandoned handler finish after the response was sent
+	close(release)
+	if err := <-lateWrite; err == nil {
+		t.Error("Expected the late write to fail")
+	}
+
+	if elapsed > time.Second {
+		t.Errorf("Expected the timeout response without waiting for the handler, took %v", elapsed)
+	}
+	if w.Code != vayu.StatusServiceUnavailable {
+		t.Errorf("Expected status code %d, got %d", vayu.StatusServiceUnavailable, w.Code)
+	}
+	if w.Body.String() != "try again later" {
+		t.Errorf("Expected timeout body, got '%s'", w.Body.String())
+	}
+	if w.Header().Get("Content-Type") != "text/plain" {
+		t.Errorf("Expected Content-Type 'text/plain', got '%s'", w.Header().Get("Content-Type"))
+	}
+	if loggedStatus != vayu.StatusServiceUnavailable {
+		t.Errorf("Expected outer middleware to see status %d, got %d", vayu.StatusServiceUnavailable, loggedStatus)
+	}
+}
+
+func TestTimeoutMiddlewareGroupDefaults(t *testing.T) {
+	app := vayu.New()
+	api := app.Group("/api")
+	api.Use(vayu.Timeout(vayu.TimeoutConfig{Timeout: 10 * time.Millisecond}))
+
+	var ctxErr error
+	done := make(chan struct{})
+	api.GET("/wait", func(c *vayu.Context, next vayu.NextFunc) {
+		// Well-behaved handlers stop when the context is done
+		<-c.Done()
+		ctxErr = c.Err()
+		close(done)
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/api/wait", nil))
+	<-done
+
+	if w.Code != vayu.StatusGatewayTimeout {
+		t.Errorf("Expected status code %d, got %d", vayu.StatusGatewayTimeout, w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("Expected JSON timeout body, got Content-Type '%s'", w.Header().Get("Content-Type"))
+	}
+	if !errors.Is(ctxErr, context.DeadlineExceeded) {
+		t.Errorf("Expected handler context to report DeadlineExceeded, got %v", ctxErr)
+	}
+}
+
+func TestTimeoutMiddlewarePanic(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.Use(vayu.Timeout(vayu.TimeoutConfig{Timeout: time.Second}))
+	app.GET("/panic", func(c *vayu.Context, next vayu.NextFunc) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+
+	if w.Code != vayu.StatusInternalServerError {
+		t.Errorf("Expected panic to reach the error handler with status %d, got %d", vayu.StatusInternalServerError, w.Code)
+	}
+}
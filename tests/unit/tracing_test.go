@@ -0,0 +1,149 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kaushiksamanta/vayu"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	sc, err := vayu.ParseTraceParent(testTraceParent)
+	if err != nil {
+		t.Fatalf("Failed to parse traceparent: %v", err)
+	}
+	if sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Unexpected trace ID %s", sc.TraceID)
+	}
+	if sc.SpanID.String() != "00f067aa0ba902b7" {
+		t.Errorf("Unexpected span ID %s", sc.SpanID)
+	}
+	if !sc.IsSampled() {
+		t.Error("Expected sampled flag to be set")
+	}
+	if sc.TraceParent() != testTraceParent {
+		t.Errorf("Expected round trip to '%s', got '%s'", testTraceParent, sc.TraceParent())
+	}
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+	}
+	for _, value := range invalid {
+		if _, err := vayu.ParseTraceParent(value); err == nil {
+			t.Errorf("Expected error for traceparent %q", value)
+		}
+	}
+
+	// Future versions may carry extra fields
+	if _, err := vayu.ParseTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"); err != nil {
+		t.Errorf("Expected future version to parse, got %v", err)
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	tracer := vayu.NewInMemoryTracer()
+	app := vayu.New()
+	app.Use(vayu.Tracing(tracer))
+
+	var outbound *http.Request
+	var handlerSpan vayu.SpanContext
+	app.GET("/users/:id", func(c *vayu.Context, next vayu.NextFunc) {
+		handlerSpan = vayu.SpanContextFromContext(c.Request.Context())
+		outbound, _ = http.NewRequest("GET", "http://upstream.local/", nil)
+		c.PropagateTraceContext(outbound)
+		c.Send(vayu.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/users/42", nil)
+	req.Header.Set("traceparent", testTraceParent)
+	req.Header.Set("tracestate", "vendor=abc")
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := tracer.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name != "GET /users/:id" {
+		t.Errorf("Expected span name 'GET /users/:id', got '%s'", span.Name)
+	}
+	if span.Parent.SpanID.String() != "00f067aa0ba902b7" || !span.Parent.Remote {
+		t.Errorf("Expected remote parent span 00f067aa0ba902b7, got %+v", span.Parent)
+	}
+	if span.Context.TraceID != span.Parent.TraceID {
+		t.Error("Expected span to continue the incoming trace")
+	}
+	if span.Attributes["http.response.status_code"] != vayu.StatusOK {
+		t.Errorf("Expected status attribute 200, got %v", span.Attributes["http.response.status_code"])
+	}
+	if handlerSpan.SpanID != span.Context.SpanID {
+		t.Error("Expected request context to carry the request span")
+	}
+
+	want := "00-4bf92f3577b34da6a3ce929d0e0e4736-" + span.Context.SpanID.String() + "-01"
+	if got := outbound.Header.Get("traceparent"); got != want {
+		t.Errorf("Expected outbound traceparent '%s', got '%s'", want, got)
+	}
+	if got := outbound.Header.Get("tracestate"); got != "vendor=abc" {
+		t.Errorf("Expected outbound tracestate 'vendor=abc', got '%s'", got)
+	}
+}
+
+func TestTracingNewTraceAndErrors(t *testing.T) {
+	tracer := vayu.NewInMemoryTracer()
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.Use(vayu.Tracing(tracer))
+	app.GET("/fail", func(c *vayu.Context, next vayu.NextFunc) {
+		panic("boom")
+	})
+
+	app.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/fail", nil))
+
+	spans := tracer.Spans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	if spans[0].Parent.IsValid() || !spans[0].Context.IsValid() {
+		t.Errorf("Expected a new root trace, got %+v", spans[0])
+	}
+	if len(spans[0].Errors) == 0 {
+		t.Error("Expected panic to be recorded on the span")
+	}
+}
+
+func TestNoopTracerPropagation(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.Tracing(nil))
+
+	var header http.Header
+	app.GET("/", func(c *vayu.Context, next vayu.NextFunc) {
+		header = http.Header{}
+		vayu.InjectTraceContext(c.Ctx, header)
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("traceparent", testTraceParent)
+	app.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := header.Get("traceparent"); got != testTraceParent {
+		t.Errorf("Expected no-op tracer to pass traceparent through, got '%s'", got)
+	}
+
+	empty := http.Header{}
+	vayu.InjectTraceContext(context.Background(), empty)
+	if len(empty) != 0 {
+		t.Errorf("Expected no headers without a span context, got %v", empty)
+	}
+}
@@ -0,0 +1,247 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaushiksamanta/vayu"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderLine struct {
+	SKU      string `json:"sku" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=100"`
+}
+
+type address struct {
+	City    string `json:"city" validate:"required"`
+	Country string `json:"country" validate:"oneof=IN US DE"`
+}
+
+type createOrder struct {
+	Email    string      `json:"email" validate:"required,email"`
+	Name     string      `json:"name" validate:"min=2,max=10"`
+	Priority string      `json:"priority" validate:"oneof=low normal high"`
+	Customer string      `json:"customer_id" validate:"uuid"`
+	Lines    []orderLine `json:"lines" validate:"required,max=3"`
+	Shipping address     `json:"shipping"`
+	Billing  *address    `json:"billing"`
+	Note     *string     `json:"note" validate:"max=5"`
+}
+
+func violationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var ve *vayu.ValidationError
+	if !assert.True(t, errors.As(err, &ve), "expected a ValidationError, got %v", err) {
+		return nil
+	}
+	fields := make(map[string]string)
+	for _, v := range ve.Violations {
+		fields[v.Field] = v.Rule
+	}
+	return fields
+}
+
+func TestValidateValid(t *testing.T) {
+	order := createOrder{
+		Email:    "ada@example.com",
+		Name:     "Ada",
+		Priority: "high",
+		Customer: "123e4567-e89b-12d3-a456-426614174000",
+		Lines:    []orderLine{{SKU: "book", Quantity: 2}},
+		Shipping: address{City: "Pune", Country: "IN"},
+	}
+	assert.NoError(t, vayu.Validate(order))
+	assert.NoError(t, vayu.Validate(&order))
+}
+
+func TestValidateViolations(t *testing.T) {
+	note := "far too long"
+	order := createOrder{
+		Email:    "not-an-email",
+		Name:     "A",
+		Priority: "urgent",
+		Customer: "42",
+		Lines: []orderLine{
+			{SKU: "book", Quantity: 1},
+			{Quantity: 0},
+		},
+		Shipping: address{Country: "FR"},
+		Billing:  &address{City: "Berlin", Country: "DE"},
+		Note:     &note,
+	}
+
+	err := vayu.Validate(&order)
+	assert.Equal(t, map[string]string{
+		"email":             "email",
+		"name":              "min",
+		"priority":          "oneof",
+		"customer_id":       "uuid",
+		"lines[1].sku":      "required",
+		"lines[1].quantity": "min",
+		"shipping.city":     "required",
+		"shipping.country":  "oneof",
+		"note":              "max",
+	}, violationFields(t, err))
+	assert.Contains(t, err.Error(), "name must have at least 2 characters")
+	assert.Contains(t, err.Error(), "lines[1].quantity must be at least 1")
+	assert.Contains(t, err.Error(), "priority must be one of low, normal, high")
+}
+
+func TestValidateRequiredAndOptional(t *testing.T) {
+	// Empty optional fields skip their rules; required stops at the first failure
+	err := vayu.Validate(createOrder{Shipping: address{City: "Pune", Country: "US"}})
+	assert.Equal(t, map[string]string{
+		"email": "required",
+		"lines": "required",
+	}, violationFields(t, err))
+
+	err = vayu.Validate(createOrder{
+		Email:    "ada@example.com",
+		Lines:    make([]orderLine, 4),
+		Shipping: address{City: "Pune", Country: "US"},
+	})
+	fields := violationFields(t, err)
+	assert.Equal(t, "max", fields["lines"])
+}
+
+func TestValidateInvalidTag(t *testing.T) {
+	type broken struct {
+		Name string `validate:"min=three"`
+	}
+	err := vayu.Validate(broken{Name: "x"})
+	assert.Error(t, err)
+	var ve *vayu.ValidationError
+	assert.False(t, errors.As(err, &ve))
+	assert.Contains(t, err.Error(), "min needs a numeric parameter")
+}
+
+func TestValidateIgnoresUnknownRules(t *testing.T) {
+	type foreign struct {
+		Age  int      `validate:"gte=18,min=1"`
+		Tags []string `validate:"dive,required"`
+	}
+	assert.NoError(t, vayu.Validate(foreign{Age: 5, Tags: []string{"a"}}))
+
+	fields := violationFields(t, vayu.Validate(foreign{Age: 0}))
+	assert.Equal(t, "min", fields["Age"])
+}
+
+func TestValidateOmitEmpty(t *testing.T) {
+	type filter struct {
+		Limit int    `validate:"omitempty,min=10"`
+		Code  string `validate:"omitempty,uuid"`
+	}
+	assert.NoError(t, vayu.Validate(filter{}))
+
+	fields := violationFields(t, vayu.Validate(filter{Limit: 5, Code: "nope"}))
+	assert.Equal(t, "min", fields["Limit"])
+	assert.Equal(t, "uuid", fields["Code"])
+}
+
+func TestBindingValidatesAutomatically(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.POST("/orders", func(c *vayu.Context, next vayu.NextFunc) {
+		order := vayu.MustBindJSONBody[createOrder](c)
+		c.Created(order)
+	})
+
+	type search struct {
+		Term string `query:"q" validate:"required,min=3"`
+		Page int    `query:"page" validate:"min=1"`
+	}
+	app.GET("/search", func(c *vayu.Context, next vayu.NextFunc) {
+		params := vayu.MustBindQueryParams[search](c)
+		c.OK(params)
+	})
+
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"email":"ada@example.com","lines":[{"sku":"x","quantity":500}],"shipping":{"city":"Pune","country":"IN"}}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, req)
+
+	assert.Equal(t, vayu.StatusUnprocessableEntity, resp.Code)
+	var body struct {
+		Error   string           `json:"error"`
+		Details []vayu.Violation `json:"details"`
+	}
+	if assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body)) {
+		assert.Equal(t, []vayu.Violation{{
+			Field:   "lines[0].quantity",
+			Rule:    "max",
+			Param:   "100",
+			Message: "must be at most 100",
+		}}, body.Details)
+	}
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/search?q=go&page=0", nil))
+	assert.Equal(t, vayu.StatusUnprocessableEntity, resp.Code)
+	assert.Contains(t, resp.Body.String(), `"field":"q"`)
+	assert.Contains(t, resp.Body.String(), `"field":"page"`)
+
+	resp = httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/search?q=golang&page=2", nil))
+	assert.Equal(t, vayu.StatusOK, resp.Code)
+}
+
+func TestBindingErrorDetails(t *testing.T) {
+	app := vayu.New()
+	app.Use(vayu.ErrorHandlerMiddleware(nil))
+	app.GET("/search", func(c *vayu.Context, next vayu.NextFunc) {
+		type search struct {
+			Page int `query:"page"`
+		}
+		c.OK(vayu.MustBindQueryParams[search](c))
+	})
+
+	resp := httptest.NewRecorder()
+	app.ServeHTTP(resp, httptest.NewRequest("GET", "/search?page=two", nil))
+	assert.Equal(t, vayu.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{
+		"error": "query parameter page: cannot convert 'two' to int: strconv.ParseInt: parsing \"two\": invalid syntax",
+		"details": [{"source": "query", "field": "page", "message": "query parameter page: cannot convert 'two' to int: strconv.ParseInt: parsing \"two\": invalid syntax"}]
+	}`, resp.Body.String())
+}
+
+func TestValidateCyclicValue(t *testing.T) {
+	type node struct {
+		Name string `validate:"required"`
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+
+	done := make(chan error, 1)
+	go func() { done <- vayu.Validate(n) }()
+
+	select {
+	case err := <-done:
+		var verr *vayu.ValidationError
+		if assert.ErrorAs(t, err, &verr) {
+			assert.Len(t, verr.Violations, 1)
+			assert.Equal(t, "Name", verr.Violations[0].Field)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Validate did not terminate on a cyclic value")
+	}
+}
+
+func TestValidateSharedPointer(t *testing.T) {
+	type address struct {
+		City string `validate:"required"`
+	}
+	type order struct {
+		Billing  *address
+		Shipping *address
+	}
+	shared := &address{}
+
+	fields := violationFields(t, vayu.Validate(order{Billing: shared, Shipping: shared}))
+	assert.Equal(t, map[string]string{"Billing.City": "required", "Shipping.City": "required"}, fields)
+}
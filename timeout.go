@@ -0,0 +1,167 @@
+package vayu
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// TimeoutConfig configures the Timeout middleware.
+type TimeoutConfig struct {
+	// Timeout is the maximum time the rest of the chain may run.
+	Timeout time.Duration
+
+	// StatusCode is sent when the timeout expires. Defaults to 504 Gateway
+	// Timeout; StatusServiceUnavailable is the other common choice.
+	StatusCode int
+
+	// Body is sent when the timeout expires.
+	// Defaults to a JSON error object like the other error helpers.
+	Body string
+
+	// ContentType is the Content-Type of Body. Defaults to application/json.
+	ContentType string
+}
+
+// Timeout returns middleware that runs the rest of the chain with a deadline.
+// The handler writes into a buffer; if it finishes in time the buffered
+// response is sent, otherwise the client immediately receives the configured
+// timeout response and anything the handler writes later is discarded.
+//
+// The handler keeps running on its own goroutine until it returns, so it
+// should watch c.Done() to stop early. Apply it per route with
+// WithMiddleware or per group with Group.Use:
+//
+//	app.GET("/report", vayu.WithMiddleware(report, vayu.Timeout(vayu.TimeoutConfig{Timeout: 5 * time.Second})))
+func Timeout(config TimeoutConfig) HandlerFunc {
+	if config.StatusCode == 0 {
+		config.StatusCode = StatusGatewayTimeout
+	}
+	if config.Body == "" && config.ContentType == "" {
+		config.Body = `{"error":"Request timed out"}`
+	}
+	if config.ContentType == "" {
+		config.ContentType = "application/json"
+	}
+
+	return func(c *Context, next NextFunc) {
+		if config.Timeout <= 0 {
+			next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.baseContext(), config.Timeout)
+		defer cancel()
+
+		// Start from the headers set so far, such as X-Request-ID
+		tw := &timeoutWriter{header: c.Writer.Header().Clone()}
+		fc := c.fork(ctx, tw)
+
+		finished := make(chan any, 1)
+		go func() {
+			defer func() {
+				r := recover()
+				if r != nil && tw.expired() {
+					// Nobody is waiting any more; report the panic instead of crashing
+					fc.Logger().Error("panic after request timed out", "error", fmt.Sprint(r))
+					r = nil
+				}
+				finished <- r
+			}()
+			fc.next()
+		}()
+
+		select {
+		case r := <-finished:
+			c.join(fc)
+			if r != nil {
+				panic(r)
+			}
+			tw.flushTo(c.Writer)
+
+		case <-ctx.Done():
+			tw.timeout()
+			// Abandon the forked context; it is never returned to the pool
+			c.Stopped = true
+			c.index = len(c.handlers)
+
+			c.Logger().Warn("request timed out", "timeout", config.Timeout)
+			if !c.Writer.Written() {
+				c.Writer.Header().Set("Content-Type", config.ContentType)
+				c.Writer.WriteHeader(config.StatusCode)
+				if _, err := c.Writer.Write([]byte(config.Body)); err != nil {
+					c.Logger().Error("error writing timeout response", "error", err)
+				}
+			}
+		}
+	}
+}
+
+// timeoutWriter buffers a handler's response until it completes, and
+// discards everything written after the timeout fired.
+type timeoutWriter struct {
+	mu       sync.Mutex
+	header   http.Header
+	buf      bytes.Buffer
+	code     int
+	timedOut bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.code != 0 {
+		return
+	}
+	tw.code = code
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if tw.code == 0 {
+		tw.code = StatusOK
+	}
+	return tw.buf.Write(b)
+}
+
+// timeout marks the writer as expired so later writes are discarded.
+func (tw *timeoutWriter) timeout() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	tw.timedOut = true
+}
+
+// expired reports whether the timeout has fired.
+func (tw *timeoutWriter) expired() bool {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	return tw.timedOut
+}
+
+// flushTo copies the buffered response to w, replacing its headers with the
+// handler's. It must only be called after the handler goroutine has finished.
+func (tw *timeoutWriter) flushTo(w *ResponseWriter) {
+	dst := w.Header()
+	clear(dst)
+	for k, v := range tw.header {
+		dst[k] = v
+	}
+	if tw.code == 0 {
+		return
+	}
+	w.WriteHeader(tw.code)
+	if tw.buf.Len() > 0 {
+		w.Write(tw.buf.Bytes())
+	}
+}
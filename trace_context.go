@@ -0,0 +1,213 @@
+package vayu
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// W3C Trace Context header names.
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// maxTraceStateLength is the maximum tracestate length defined by the W3C spec.
+const maxTraceStateLength = 512
+
+// ErrInvalidTraceParent is returned when a traceparent header is malformed.
+var ErrInvalidTraceParent = errors.New("invalid traceparent header")
+
+// TraceID is a 16-byte W3C trace identifier.
+type TraceID [16]byte
+
+// String returns the lowercase hex encoding of the trace ID.
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// IsValid reports whether the trace ID is not all zeros.
+func (t TraceID) IsValid() bool {
+	return t != TraceID{}
+}
+
+// SpanID is an 8-byte W3C span identifier.
+type SpanID [8]byte
+
+// String returns the lowercase hex encoding of the span ID.
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// IsValid reports whether the span ID is not all zeros.
+func (s SpanID) IsValid() bool {
+	return s != SpanID{}
+}
+
+// FlagSampled is the W3C trace flag marking a trace as sampled.
+const FlagSampled byte = 0x01
+
+// SpanContext identifies a span and carries its W3C propagation state.
+type SpanContext struct {
+	TraceID    TraceID
+	SpanID     SpanID
+	Flags      byte
+	TraceState string
+	// Remote is true when the span context was received from another service.
+	Remote bool
+}
+
+// IsValid reports whether both the trace and span IDs are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// IsSampled reports whether the sampled flag is set.
+func (sc SpanContext) IsSampled() bool {
+	return sc.Flags&FlagSampled != 0
+}
+
+// TraceParent formats the span context as a version 00 traceparent header value.
+func (sc SpanContext) TraceParent() string {
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + hex.EncodeToString([]byte{sc.Flags})
+}
+
+// ParseTraceParent parses a traceparent header value such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseTraceParent(value string) (SpanContext, error) {
+	var sc SpanContext
+	value = strings.TrimSpace(value)
+
+	// version(2) - trace-id(32) - parent-id(16) - flags(2)
+	if len(value) < 55 || value[2] != '-' || value[35] != '-' || value[52] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+
+	version, ok := decodeLowerHex(value[0:2])
+	if !ok || version[0] == 0xff {
+		return sc, ErrInvalidTraceParent
+	}
+	// Version 00 has exactly four fields; future versions may append more
+	if version[0] == 0 && len(value) != 55 {
+		return sc, ErrInvalidTraceParent
+	}
+	if len(value) > 55 && value[55] != '-' {
+		return sc, ErrInvalidTraceParent
+	}
+
+	traceID, ok := decodeLowerHex(value[3:35])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+	spanID, ok := decodeLowerHex(value[36:52])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+	flags, ok := decodeLowerHex(value[53:55])
+	if !ok {
+		return sc, ErrInvalidTraceParent
+	}
+
+	copy(sc.TraceID[:], traceID)
+	copy(sc.SpanID[:], spanID)
+	sc.Flags = flags[0]
+	if !sc.IsValid() {
+		return SpanContext{}, ErrInvalidTraceParent
+	}
+	return sc, nil
+}
+
+// decodeLowerHex decodes s, rejecting uppercase digits as the spec requires.
+func decodeLowerHex(s string) ([]byte, bool) {
+	for i := 0; i < len(s); i++ {
+		if ch := s[i]; !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'f') {
+			return nil, false
+		}
+	}
+	b, err := hex.DecodeString(s)
+	return b, err == nil
+}
+
+// ExtractTraceContext reads the traceparent and tracestate headers.
+// It returns false when no valid traceparent is present.
+func ExtractTraceContext(h http.Header) (SpanContext, bool) {
+	sc, err := ParseTraceParent(h.Get(HeaderTraceParent))
+	if err != nil {
+		return SpanContext{}, false
+	}
+	sc.Remote = true
+
+	// Multiple tracestate headers are combined into one list
+	state := strings.Join(h.Values(HeaderTraceState), ",")
+	if len(state) <= maxTraceStateLength {
+		sc.TraceState = strings.TrimSpace(state)
+	}
+	return sc, true
+}
+
+// InjectTraceContext writes the span context stored in ctx as traceparent
+// and tracestate headers. It does nothing when ctx carries no valid span.
+func InjectTraceContext(ctx context.Context, h http.Header) {
+	sc := SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	h.Set(HeaderTraceParent, sc.TraceParent())
+	if sc.TraceState != "" {
+		h.Set(HeaderTraceState, sc.TraceState)
+	} else {
+		h.Del(HeaderTraceState)
+	}
+}
+
+// PropagateTraceContext sets the current span context on an outbound request.
+func (c *Context) PropagateTraceContext(req *http.Request) {
+	InjectTraceContext(c, req.Header)
+}
+
+// spanContextKey is the context.Context key under which the span context is stored.
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a copy of ctx carrying sc.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context stored in ctx, or the zero
+// value when there is none.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if ctx == nil {
+		return SpanContext{}
+	}
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// NewTraceID returns a random trace ID.
+func NewTraceID() TraceID {
+	var id TraceID
+	for !id.IsValid() {
+		readRandom(id[:])
+	}
+	return id
+}
+
+// NewSpanID returns a random span ID.
+func NewSpanID() SpanID {
+	var id SpanID
+	for !id.IsValid() {
+		readRandom(id[:])
+	}
+	return id
+}
+
+// readRandom fills b from crypto/rand.
+func readRandom(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand never fails on supported platforms
+		panic(err)
+	}
+}
@@ -0,0 +1,195 @@
+package vayu
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans. Implementations can bridge to OpenTelemetry or any
+// other tracing system; vayu ships a no-op and an in-memory implementation.
+//
+// Start is given a context that may carry a parent span context (see
+// SpanContextFromContext) and returns the span together with the context
+// the span should run in.
+type Tracer interface {
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a single timed operation within a trace.
+type Span interface {
+	// SpanContext returns the identifiers propagated to child spans.
+	SpanContext() SpanContext
+	// SetAttribute records a key/value pair on the span.
+	SetAttribute(key string, value any)
+	// RecordError marks the span as failed.
+	RecordError(err error)
+	// End completes the span. Calls after the first have no effect.
+	End()
+}
+
+// Tracing returns middleware that continues the W3C trace from the incoming
+// traceparent/tracestate headers and wraps every request in a span named
+// "METHOD /route/:pattern". The span context is stored in c.Ctx and the
+// request context, so PropagateTraceContext and InjectTraceContext can
+// forward it to outbound calls. A nil tracer uses NoopTracer.
+func Tracing(tracer Tracer) HandlerFunc {
+	if tracer == nil {
+		tracer = NoopTracer{}
+	}
+
+	return func(c *Context, next NextFunc) {
+		ctx := c.baseContext()
+		if parent, ok := ExtractTraceContext(c.Request.Header); ok {
+			ctx = ContextWithSpanContext(ctx, parent)
+		}
+
+		route := c.RoutePattern()
+		ctx, span := tracer.Start(ctx, c.Request.Method+" "+route)
+		ctx = ContextWithSpanContext(ctx, span.SpanContext())
+
+		span.SetAttribute("http.request.method", c.Request.Method)
+		span.SetAttribute("http.route", route)
+		span.SetAttribute("url.path", c.Request.URL.Path)
+
+		c.SetContext(ctx)
+		// Rebuild the request-scoped logger with the trace identifiers
+		c.logger = nil
+
+		defer func() {
+			if r := recover(); r != nil {
+				span.RecordError(fmt.Errorf("panic: %v", r))
+				span.SetAttribute("http.response.status_code", StatusInternalServerError)
+				span.End()
+				panic(r)
+			}
+
+			status := c.Writer.Status()
+			if status == 0 {
+				status = StatusOK
+			}
+			if status >= StatusInternalServerError {
+				span.RecordError(fmt.Errorf("server error: status %d", status))
+			}
+			span.SetAttribute("http.response.status_code", status)
+			span.End()
+		}()
+
+		next()
+	}
+}
+
+// NoopTracer is a Tracer that records nothing. Its spans pass the parent
+// span context through unchanged, so propagation still works.
+type NoopTracer struct{}
+
+// Start implements Tracer.
+func (NoopTracer) Start(ctx context.Context, _ string) (context.Context, Span) {
+	return ctx, noopSpan{sc: SpanContextFromContext(ctx)}
+}
+
+type noopSpan struct {
+	sc SpanContext
+}
+
+func (s noopSpan) SpanContext() SpanContext { return s.sc }
+func (noopSpan) SetAttribute(string, any)   {}
+func (noopSpan) RecordError(error)          {}
+func (noopSpan) End()                       {}
+
+// InMemoryTracer is a Tracer that keeps finished spans in memory.
+// It is intended for tests.
+type InMemoryTracer struct {
+	mu    sync.Mutex
+	spans []*RecordedSpan
+}
+
+// NewInMemoryTracer creates an empty InMemoryTracer.
+func NewInMemoryTracer() *InMemoryTracer {
+	return &InMemoryTracer{}
+}
+
+// RecordedSpan is a span captured by InMemoryTracer.
+type RecordedSpan struct {
+	Name       string
+	Context    SpanContext
+	Parent     SpanContext
+	Attributes map[string]any
+	Errors     []error
+	StartTime  time.Time
+	EndTime    time.Time
+
+	tracer *InMemoryTracer
+	ended  bool
+}
+
+// Start implements Tracer. The new span joins the parent's trace when ctx
+// carries a valid span context, otherwise it starts a new sampled trace.
+func (t *InMemoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent := SpanContextFromContext(ctx)
+
+	sc := SpanContext{SpanID: NewSpanID(), Flags: FlagSampled}
+	if parent.IsValid() {
+		sc.TraceID = parent.TraceID
+		sc.Flags = parent.Flags
+		sc.TraceState = parent.TraceState
+	} else {
+		sc.TraceID = NewTraceID()
+	}
+
+	span := &RecordedSpan{
+		Name:       name,
+		Context:    sc,
+		Parent:     parent,
+		Attributes: make(map[string]any),
+		StartTime:  time.Now(),
+		tracer:     t,
+	}
+	return ContextWithSpanContext(ctx, sc), span
+}
+
+// Spans returns the spans that have ended, in the order they ended.
+func (t *InMemoryTracer) Spans() []*RecordedSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*RecordedSpan(nil), t.spans...)
+}
+
+// Reset discards all recorded spans.
+func (t *InMemoryTracer) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.spans = nil
+}
+
+// SpanContext implements Span.
+func (s *RecordedSpan) SpanContext() SpanContext {
+	return s.Context
+}
+
+// SetAttribute implements Span.
+func (s *RecordedSpan) SetAttribute(key string, value any) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.Attributes[key] = value
+}
+
+// RecordError implements Span.
+func (s *RecordedSpan) RecordError(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.Errors = append(s.Errors, err)
+}
+
+// End implements Span.
+func (s *RecordedSpan) End() {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	if s.ended {
+		return
+	}
+	s.ended = true
+	s.EndTime = time.Now()
+	s.tracer.spans = append(s.tracer.spans, s)
+}
@@ -0,0 +1,75 @@
+package vayu
+
+import (
+	"encoding/hex"
+	"errors"
+)
+
+// UUID is an RFC 4122 universally unique identifier.
+type UUID [16]byte
+
+// ErrInvalidUUID is returned when a string is not a valid UUID.
+var ErrInvalidUUID = errors.New("invalid UUID")
+
+// NewUUID returns a random (version 4) UUID.
+func NewUUID() UUID {
+	var u UUID
+	readRandom(u[:])
+	u[6] = (u[6] & 0x0f) | 0x40 // version 4
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return u
+}
+
+// ParseUUID parses the canonical 36-character form,
+// e.g. "123e4567-e89b-12d3-a456-426614174000". Upper and lower case are accepted.
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return u, ErrInvalidUUID
+	}
+	groups := [5][2]int{{0, 8}, {9, 13}, {14, 18}, {19, 23}, {24, 36}}
+	n := 0
+	for _, g := range groups {
+		b, err := hex.DecodeString(s[g[0]:g[1]])
+		if err != nil {
+			return UUID{}, ErrInvalidUUID
+		}
+		n += copy(u[n:], b)
+	}
+	return u, nil
+}
+
+// String returns the canonical lowercase form of the UUID.
+func (u UUID) String() string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// IsZero reports whether u is the nil UUID.
+func (u UUID) IsZero() bool {
+	return u == UUID{}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (u *UUID) UnmarshalText(text []byte) error {
+	parsed, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = parsed
+	return nil
+}
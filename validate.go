@@ -0,0 +1,309 @@
+package vayu
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Violation describes a field that failed a validation rule.
+type Violation struct {
+	// Field is the path to the field, using the names the client sent, e.g.
+	// "items[0].quantity".
+	Field string `json:"field"`
+	// Rule is the failed rule, e.g. "min".
+	Rule string `json:"rule"`
+	// Param is the rule's parameter, e.g. "1" for min=1.
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every rule a value violated.
+// Through the default error handler it produces a 422 Unprocessable Entity
+// whose body includes the violations under "details".
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		msgs[i] = v.Field + " " + v.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// StatusCode implements StatusCoder.
+func (e *ValidationError) StatusCode() int {
+	return StatusUnprocessableEntity
+}
+
+// ErrorDetails implements ErrorDetailer.
+func (e *ValidationError) ErrorDetails() any {
+	return e.Violations
+}
+
+// Validate checks v against the rules in its `validate` struct tags and
+// returns a *ValidationError listing every violation, or nil. Rules are
+// comma-separated:
+//
+//	required    the value must not be the zero value
+//	min=N       minimum length for strings, slices and maps, minimum value for numbers
+//	max=N       maximum length or value
+//	email       a plain email address such as user@example.com
+//	oneof=a b   one of the space-separated values
+//	uuid        a canonical UUID string
+//	omitempty   skip the remaining rules when the value is the zero value
+//
+// Rules other than required are skipped for empty strings, slices, maps
+// and nil pointers, so optional fields are only checked when present.
+// Unknown rules are ignored, so tags written for other validators, such as
+// gte or dive, do not cause errors.
+// Nested structs, pointers to structs and slices of structs are validated
+// recursively. The binding functions call Validate automatically.
+func Validate(v any) error {
+	var violations []Violation
+	if err := validateValue(reflect.ValueOf(v), "", &violations, make(map[visit]bool)); err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return &ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// visit identifies a pointer validateValue is following. The type is part
+// of the key because a struct and its first field share an address.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// validateValue walks val, validating tagged struct fields and descending
+// into nested structs and slices. path is the field path of val. visited
+// holds the pointers on the way to val; meeting one again means a cycle, so
+// it is skipped, while pointers shared by several fields are validated at
+// each of them.
+func validateValue(val reflect.Value, path string, violations *[]Violation, visited map[visit]bool) error {
+	switch val.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if val.IsNil() {
+			return nil
+		}
+		if val.Kind() == reflect.Pointer {
+			key := visit{val.Pointer(), val.Type()}
+			if visited[key] {
+				return nil
+			}
+			visited[key] = true
+			defer delete(visited, key)
+		}
+		return validateValue(val.Elem(), path, violations, visited)
+
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < val.Len(); i++ {
+			if err := validateValue(val.Index(i), fmt.Sprintf("%s[%d]", path, i), violations, visited); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Struct:
+		if val.Type() == timeType {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	typ := val.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		fieldPath := fieldName(field)
+		if field.Anonymous {
+			// Embedded struct fields are promoted to the parent's path
+			fieldPath = path
+		} else if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+
+		fieldValue := val.Field(i)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := applyRules(fieldValue, tag, fieldPath, violations); err != nil {
+				return fmt.Errorf("validate tag on %s.%s: %w", typ.Name(), field.Name, err)
+			}
+		}
+		if err := validateValue(fieldValue, fieldPath, violations, visited); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldName returns the name clients use for a field: its json, form,
+// query, path, header or cookie key, or the Go field name.
+func fieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form", "query", "path", "header", "cookie"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// applyRules checks a single field against the rules in tag.
+func applyRules(val reflect.Value, tag, path string, violations *[]Violation) error {
+	empty := isEmptyValue(val)
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		if name == "" {
+			continue
+		}
+		if name == "omitempty" {
+			if val.IsZero() {
+				return nil
+			}
+			continue
+		}
+		if name != "required" && empty {
+			continue
+		}
+
+		ok, message, err := checkRule(val, name, param)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			*violations = append(*violations, Violation{Field: path, Rule: name, Param: param, Message: message})
+			if name == "required" {
+				// The remaining rules would only repeat the problem
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// isEmptyValue reports whether val holds no value for an optional field.
+func isEmptyValue(val reflect.Value) bool {
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return val.IsNil()
+	}
+	return false
+}
+
+// checkRule reports whether val satisfies the rule, with a message for the
+// client when it does not. Unknown rules are satisfied. It returns an error
+// for malformed parameters.
+func checkRule(val reflect.Value, name, param string) (bool, string, error) {
+	if val.Kind() == reflect.Pointer && name != "required" {
+		val = val.Elem()
+	}
+
+	switch name {
+	case "required":
+		return !val.IsZero(), "is required", nil
+
+	case "min", "max":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false, "", fmt.Errorf("%s needs a numeric parameter, got %q", name, param)
+		}
+		n, isLength, err := measure(val)
+		if err != nil {
+			return false, "", fmt.Errorf("%s: %w", name, err)
+		}
+		ok := n >= limit
+		if name == "max" {
+			ok = n <= limit
+		}
+		if ok {
+			return true, "", nil
+		}
+		bound := "at least"
+		if name == "max" {
+			bound = "at most"
+		}
+		if isLength {
+			return false, fmt.Sprintf("must have %s %s %s", bound, param, lengthUnit(val)), nil
+		}
+		return false, fmt.Sprintf("must be %s %s", bound, param), nil
+
+	case "email":
+		s, err := stringValue(val, name)
+		if err != nil {
+			return false, "", err
+		}
+		addr, parseErr := mail.ParseAddress(s)
+		return parseErr == nil && addr.Address == s, "must be a valid email address", nil
+
+	case "oneof":
+		options := strings.Fields(param)
+		if len(options) == 0 {
+			return false, "", fmt.Errorf("oneof needs at least one value")
+		}
+		actual := fmt.Sprint(val.Interface())
+		for _, option := range options {
+			if actual == option {
+				return true, "", nil
+			}
+		}
+		return false, "must be one of " + strings.Join(options, ", "), nil
+
+	case "uuid":
+		s, err := stringValue(val, name)
+		if err != nil {
+			return false, "", err
+		}
+		_, parseErr := ParseUUID(s)
+		return parseErr == nil, "must be a valid UUID", nil
+	}
+	// Rules of other validators, such as gte or dive, are left to them
+	return true, "", nil
+}
+
+// measure returns the length of strings, slices and maps or the value of
+// numbers, and whether it is a length.
+func measure(val reflect.Value) (float64, bool, error) {
+	switch val.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(val.String())), true, nil
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(val.Len()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), false, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(val.Uint()), false, nil
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), false, nil
+	}
+	return 0, false, fmt.Errorf("unsupported type %s", val.Type())
+}
+
+// lengthUnit names what a length counts.
+func lengthUnit(val reflect.Value) string {
+	if val.Kind() == reflect.String {
+		return "characters"
+	}
+	return "items"
+}
+
+// stringValue returns the value of a string field for string-only rules.
+func stringValue(val reflect.Value, rule string) (string, error) {
+	if val.Kind() != reflect.String {
+		return "", fmt.Errorf("%s applies to strings, not %s", rule, val.Type())
+	}
+	return val.String(), nil
+}
@@ -4,18 +4,47 @@ package vayu
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/netip"
+	"os"
 	"strings"
+	"sync"
 	"time"
 )
 
+// DefaultRequestTimeout is the request deadline applied by New.
+const DefaultRequestTimeout = 30 * time.Second
+
 // App represents a vayu web application.
 // It contains the router, middleware stack, and serves HTTP requests.
 type App struct {
 	router          *Router
 	middleware      []HandlerFunc
 	NotFoundHandler HandlerFunc
+
+	// RequestTimeout bounds every request's context. Zero disables the deadline.
+	// It also ends streaming responses such as NDJSON, which stop when the
+	// context is done; set it to zero and bound other routes with the
+	// Timeout middleware when the app serves long-lived streams.
+	RequestTimeout time.Duration
+
+	logger   *slog.Logger
+	logLevel *slog.LevelVar
+
+	jsonBinding JSONBindingConfig
+	cookieKeys  *CookieKeys
+	templates   *templateEngine
+
+	safeRedirects *SafeRedirectConfig
+	// lastRoute is the pattern of the most recently registered route, for Name
+	lastRoute string
+
+	// trustedProxies are the networks whose forwarding headers are honored
+	trustedProxies []netip.Prefix
+
+	// pool recycles Context objects between requests
+	pool sync.Pool
 }
 
 // NextFunc represents the next middleware or handler function to be called.
@@ -30,8 +59,16 @@ func New() *App {
 	app := &App{
 		router: &Router{
 			routes: make(map[string][]route),
+			names:  make(map[string]string),
 		},
+		RequestTimeout: DefaultRequestTimeout,
+		logLevel:       new(slog.LevelVar),
+	}
+	app.pool.New = func() any {
+		return newContext(app)
 	}
+	app.logLevel.Set(defaultLogLevel())
+	app.logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: app.logLevel}))
 
 	// Default 404 handler
 	app.NotFoundHandler = func(c *Context, _ NextFunc) {
@@ -39,7 +76,7 @@ func New() *App {
 		_, err := c.Writer.Write([]byte("404 Not Found"))
 		if err != nil {
 			// Log error but can't do much else in a 404 handler
-			log.Printf("Error writing 404 response: %v", err)
+			c.Logger().Error("error writing 404 response", "error", err)
 		}
 	}
 
@@ -50,18 +87,14 @@ func New() *App {
 // Middleware functions are executed in the order they are added.
 func (a *App) Use(mw HandlerFunc) *App {
 	a.middleware = append(a.middleware, mw)
+	a.router.rebuild(a.middleware)
 	return a
 }
 
 // addRoute registers a route with the given HTTP method, path, and handler.
 func (a *App) addRoute(method, path string, handler HandlerFunc) *App {
-	if a.router.routes[method] == nil {
-		a.router.routes[method] = []route{}
-	}
-	a.router.routes[method] = append(a.router.routes[method], route{
-		pattern: path,
-		handler: handler,
-	})
+	a.router.add(method, path, handler, a.middleware)
+	a.lastRoute = path
 	return a
 }
 
@@ -103,21 +136,20 @@ func (a *App) HEAD(path string, handler HandlerFunc) *App {
 // ServeHTTP implements the http.Handler interface.
 // This is the entry point for handling HTTP requests.
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Create a new context with a timeout of 30 seconds
-	ctxWithTimeout, cancel := context.WithTimeout(r.Context(), 30*time.Second)
-	defer cancel()
-
-	// Create the request context
-	ctx := &Context{
-		Writer:  NewResponseWriter(w),
-		Request: r.WithContext(ctxWithTimeout),
-		Params:  map[string]string{},
-		Ctx:     ctxWithTimeout,
+	if a.RequestTimeout > 0 {
+		ctxWithTimeout, cancel := context.WithTimeout(r.Context(), a.RequestTimeout)
+		defer cancel()
+		r = r.WithContext(ctxWithTimeout)
 	}
 
+	// Reuse a pooled request context
+	ctx := a.pool.Get().(*Context)
+	ctx.reset(w, r)
+	defer a.releaseContext(ctx)
+
 	// Find matching route
-	handler, params := a.router.matchRoute(r.Method, r.URL.Path)
-	if handler == nil {
+	rt := a.router.matchRoute(r.Method, r.URL.Path, ctx.Params)
+	if rt == nil {
 		// Use custom NotFoundHandler if defined
 		if a.NotFoundHandler != nil {
 			a.NotFoundHandler(ctx, func() {})
@@ -126,27 +158,18 @@ func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	ctx.Params = params
-
-	// Build middleware + handler chain
-	mws := append(a.middleware, handler)
-
-	var exec func(int)
-	exec = func(i int) {
-		// Check if request context is done
-		select {
-		case <-ctx.Ctx.Done():
-			// Just write a gateway timeout if the context deadline was exceeded
-			ctx.Writer.WriteHeader(StatusGatewayTimeout)
-			return
-		default:
-			if i < len(mws) && !ctx.Stopped {
-				mws[i](ctx, func() { exec(i + 1) })
-			}
-		}
-	}
+	ctx.route = rt.pattern
+
+	// Run the pre-composed middleware + handler chain
+	ctx.handlers = rt.chain
+	ctx.next()
+}
 
-	exec(0)
+// releaseContext drops a context's references to the finished request and
+// returns it to the pool.
+func (a *App) releaseContext(c *Context) {
+	c.reset(nil, nil)
+	a.pool.Put(c)
 }
 
 // Static serves static files from the given directory under the specified route prefix.